@@ -0,0 +1,69 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// Bold, underline and reverse video carry no colour, so they mark changed
+// lines and the current search match when colour is off.
+const (
+	noColorChangedLineStyle  = "\x1b[1m"     // Bold
+	noColorCurrentMatchStyle = "\x1b[1;4;7m" // Bold, underlined, reverse video
+)
+
+// textMarkers adds text to indicators that are otherwise conveyed by colour
+// alone, for screen readers and monochrome terminals.
+var textMarkers bool
+
+// noColorRequested follows the NO_COLOR convention (https://no-color.org):
+// any non-empty value turns colour off.
+func noColorRequested() bool {
+	return os.Getenv("NO_COLOR") != ""
+}
+
+// setAccessibility turns colour off and, in that case or in high-contrast
+// mode, turns the text markers on. It runs after applyTheme, so it overrides
+// the theme's formatter.
+func setAccessibility(noColor, highContrast bool) {
+	if noColor {
+		lipgloss.SetColorProfile(termenv.Ascii)
+		chromaFormatter = "noop"
+		currentMatchStyle = noColorCurrentMatchStyle
+		changedLineStyle = noColorChangedLineStyle
+	}
+	textMarkers = noColor || highContrast
+}
+
+// selectionMarker precedes the selected list item.
+func selectionMarker() string {
+	if textMarkers {
+		return "> "
+	}
+	return "  "
+}
+
+// logLevelMarker precedes the levels that call for attention.
+func logLevelMarker(level string) string {
+	if !textMarkers {
+		return ""
+	}
+	switch level {
+	case "ERROR":
+		return "!! "
+	case "WARN":
+		return "! "
+	}
+	return ""
+}
+
+// notificationText labels a notification that is not already an error.
+func notificationText(text string) string {
+	if !textMarkers || strings.HasPrefix(text, "Error") {
+		return text
+	}
+	return "Note: " + text
+}
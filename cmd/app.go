@@ -2,15 +2,19 @@ package main
 
 import (
 	"context"
+	"regexp"
 	"slices"
 	"sync"
 
+	"github.com/alexei-ozerov/kube-traverse/internal/fsm"
 	"github.com/alexei-ozerov/kube-traverse/internal/kube"
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/dynamic/dynamicinformer"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -21,6 +25,7 @@ type appData struct {
 	mu             sync.RWMutex
 	cancelInformer context.CancelFunc
 	cancelLog      context.CancelFunc
+	cancelTask     context.CancelFunc
 	informerWg     sync.WaitGroup
 	program        *tea.Program
 
@@ -31,6 +36,7 @@ type appData struct {
 
 	// Kube
 	clients     kube.Ctx
+	detachKeys  detachKeys
 	gvrList     []kube.ApiResource
 	selectedGvr *kube.ApiResource
 	dynFact     dynamicinformer.DynamicSharedInformerFactory
@@ -45,9 +51,59 @@ type appData struct {
 	unstructured      []*unstructured.Unstructured
 	viewport          viewport.Model
 	selectedResource  *unstructured.Unstructured
+	returnResource    *unstructured.Unstructured
 	selectedContainer string
+	previousLogs      bool
+	logGeneration     int
 	selectedSpec      string
+	specMode          specMode
+	specFormat        specFormat
+	specQuery         string
+	specChanges       map[int]bool
+	specChangeSeq     int
+	specDeleted       bool
+	treeMode          bool
+	specTree          *treeNode
+	treeExpanded      map[string]bool
+	treeCursor        int
+	explainMode       bool
+	schemas           map[schema.GroupVersion]SchemaMsg
+	specLines         []string
+	specNode          *yaml.Node
+	yankPending       bool
 	logBuffer         string
+	outputTitle       string
+	outputBuffer      string
+	outputHelp        string
+	outputOrigin      fsm.State
+	outputGeneration  int
+	revisions         []rolloutRevision
+	dataEntries       []dataEntry
+	revealedKeys      map[string]bool
+	dataMasked        bool
+	selectedDataKey   string
+
+	serviceAccountToken string
+
+	// Versions observed by the informers, per object
+	history     map[types.UID]*objectHistory
+	historyMark string
+
+	// Comparison of two objects of the same GVR
+	compareMark        *unstructured.Unstructured
+	compareTarget      *unstructured.Unstructured
+	compareIgnoreNoise bool
+	comparing          bool
+
+	// Search
+	searchPattern *regexp.Regexp
+	searchLines   []int
+	searchIndex   int
+
+	// Confirmation and input
+	confirmText string
+	confirmCmd  tea.Cmd
+	prompt      *prompt
 
 	// Export
 	exportNotification string
@@ -113,6 +169,7 @@ func (a *appData) fetchKubeData() error {
 		return err
 	}
 
+	a.clients.Config = kubeCfg
 	a.clients.Discovery = discoClient
 	a.clients.Dynamic = dynClient
 	a.clients.Typed = typedClient
@@ -0,0 +1,51 @@
+package main
+
+import (
+	"github.com/alexei-ozerov/kube-traverse/internal/kube"
+	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// containerStreamFlags reports whether the named container was started with
+// stdin and a TTY, which decides if an attach can be interactive.
+func containerStreamFlags(pod *unstructured.Unstructured, name string) (stdin, tty bool) {
+	for _, field := range []string{"containers", "initContainers", "ephemeralContainers"} {
+		containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", field)
+		for _, c := range containers {
+			cMap, ok := c.(map[string]any)
+			if !ok || cMap["name"] != name {
+				continue
+			}
+			stdin, _ = cMap["stdin"].(bool)
+			tty, _ = cMap["tty"].(bool)
+			return stdin, tty
+		}
+	}
+	return false, false
+}
+
+// attachContainer attaches to the selected container's main process. Without
+// stdin and a TTY on the container the session falls back to read-only output.
+func (m *model) attachContainer() tea.Cmd {
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		pod := m.entity.Data.selectedResource
+		container := m.entity.Data.selectedContainer
+		m.entity.Data.mu.RUnlock()
+
+		if pod == nil {
+			return NotifyMsg("Error: Missing Pod")
+		}
+
+		stdin, tty := containerStreamFlags(pod, container)
+		interactive := stdin && tty
+
+		return SessionMsg{opts: kube.StreamOptions{
+			Namespace: pod.GetNamespace(),
+			Pod:       pod.GetName(),
+			Container: container,
+			Stdin:     interactive,
+			TTY:       interactive,
+		}}
+	}
+}
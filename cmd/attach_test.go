@@ -0,0 +1,103 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestParseDetachKeys(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    []byte
+		wantErr bool
+	}{
+		{spec: "ctrl-p,ctrl-q", want: []byte{0x10, 0x11}},
+		{spec: "Ctrl-P, Ctrl-Q", want: []byte{0x10, 0x11}},
+		{spec: "ctrl-[", want: []byte{0x1b}},
+		{spec: "ctrl-@,x", want: []byte{0x00, 'x'}},
+		{spec: "q", want: []byte{'q'}},
+		{spec: "", wantErr: true},
+		{spec: "ctrl-p,", wantErr: true},
+		{spec: "ctrl-", wantErr: true},
+		{spec: "ctrl-1", wantErr: true},
+		{spec: "ctrl-pq", wantErr: true},
+		{spec: "esc", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			keys, err := parseDetachKeys(tt.spec)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("parseDetachKeys(%q) = %v, want an error", tt.spec, keys.sequence)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseDetachKeys(%q): %v", tt.spec, err)
+			}
+			if !bytes.Equal(keys.sequence, tt.want) {
+				t.Errorf("parseDetachKeys(%q) = %v, want %v", tt.spec, keys.sequence, tt.want)
+			}
+			if keys.label != tt.spec {
+				t.Errorf("label = %q, want %q", keys.label, tt.spec)
+			}
+		})
+	}
+}
+
+// chunkReader returns one chunk per Read, as a terminal delivers keystrokes.
+type chunkReader struct {
+	chunks []string
+}
+
+func (c *chunkReader) Read(p []byte) (int, error) {
+	if len(c.chunks) == 0 {
+		return 0, io.EOF
+	}
+	n := copy(p, c.chunks[0])
+	c.chunks = c.chunks[1:]
+	return n, nil
+}
+
+func TestDetachReader(t *testing.T) {
+	sequence := []byte{0x10, 0x11} // ctrl-p,ctrl-q
+
+	tests := []struct {
+		name     string
+		chunks   []string
+		want     string
+		detached bool
+	}{
+		{name: "no sequence", chunks: []string{"ls\n", "exit\n"}, want: "ls\nexit\n"},
+		{name: "sequence in one read", chunks: []string{"ab\x10\x11cd"}, want: "ab", detached: true},
+		{name: "sequence split across reads", chunks: []string{"ab\x10", "\x11cd"}, want: "ab", detached: true},
+		{name: "sequence on its own", chunks: []string{"\x10", "\x11"}, want: "", detached: true},
+		{name: "partial match released", chunks: []string{"a\x10", "b"}, want: "a\x10b"},
+		{name: "partial match restarts", chunks: []string{"\x10", "\x10\x11"}, want: "\x10", detached: true},
+		{name: "second key alone", chunks: []string{"\x11"}, want: "\x11"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detached := false
+			r := &detachReader{
+				r:        &chunkReader{chunks: tt.chunks},
+				sequence: sequence,
+				onDetach: func() { detached = true },
+			}
+
+			got, err := io.ReadAll(r)
+			if err != nil {
+				t.Fatalf("ReadAll: %v", err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("forwarded %q, want %q", got, tt.want)
+			}
+			if detached != tt.detached {
+				t.Errorf("detached = %v, want %v", detached, tt.detached)
+			}
+		})
+	}
+}
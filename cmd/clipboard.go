@@ -0,0 +1,152 @@
+package main
+
+import (
+	"os"
+	"strings"
+
+	"github.com/alexei-ozerov/kube-traverse/internal/fsm"
+	"github.com/atotto/clipboard"
+	"github.com/aymanbagabas/go-osc52/v2"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+const yankHelp = "Yank: n name • N namespace/name • y YAML • m search match • v visible text"
+
+// copyToClipboard sets the clipboard of the terminal through the OSC52 escape
+// sequence, which also reaches it over SSH and from inside tmux or screen. A
+// local clipboard tool is written as well when there is one, since some
+// terminals ignore OSC52.
+func copyToClipboard(text string) error {
+	seq := osc52.New(text)
+	if os.Getenv("TMUX") != "" {
+		seq = seq.Tmux()
+	} else if strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		seq = seq.Screen()
+	}
+
+	// Stderr is the terminal too, and bypasses the renderer on stdout
+	_, err := seq.WriteTo(os.Stderr)
+	if !clipboard.Unsupported {
+		if localErr := clipboard.WriteAll(text); localErr == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// startYank waits for the key naming what to copy. It does nothing in a list
+// that is being filtered, where y is part of the filter.
+func (m *model) startYank() (tea.Cmd, bool) {
+	if !isViewportState(m.entity.GetCurrentState()) && m.entity.Data.list.FilterState() == list.Filtering {
+		return nil, false
+	}
+
+	m.entity.Data.mu.Lock()
+	m.entity.Data.yankPending = true
+	m.entity.Data.mu.Unlock()
+	return m.notify(yankHelp), true
+}
+
+// resolveYank copies what the key after y names, if the current screen has
+// it.
+func (m *model) resolveYank(msg tea.KeyMsg) tea.Cmd {
+	m.entity.Data.mu.Lock()
+	m.entity.Data.yankPending = false
+	m.entity.Data.mu.Unlock()
+
+	state := m.entity.GetCurrentState()
+	obj := m.entity.Data.selectedResource
+	if state == resource {
+		obj, _ = m.selectedListResource()
+	}
+
+	var text, what string
+	switch msg.String() {
+	case "n":
+		if obj != nil {
+			text, what = obj.GetName(), "name"
+		}
+	case "N":
+		if obj != nil {
+			text, what = resourceName(obj), "namespace/name"
+		}
+	case "y":
+		if obj != nil && (state == resource || state == spec) {
+			// The clipboard is shared with other programs and often synced
+			if obj.GetKind() == "Secret" {
+				return m.notify("Secrets are not copied; use data* to copy a single value")
+			}
+			data, err := exportDocument(obj, specFull, formatYAML)
+			if err != nil {
+				return m.notify("Error: " + err.Error())
+			}
+			text, what = string(data), "YAML"
+		}
+	case "m":
+		m.entity.Data.mu.Lock()
+		text = m.entity.Data.currentMatch(state)
+		m.entity.Data.mu.Unlock()
+		what = "search match"
+	case "v":
+		if isViewportState(state) {
+			m.entity.Data.mu.RLock()
+			view := m.entity.Data.viewport.View()
+			m.entity.Data.mu.RUnlock()
+
+			lines := strings.Split(sgrPattern.ReplaceAllString(view, ""), "\n")
+			for i, line := range lines {
+				lines[i] = strings.TrimRight(line, " ")
+			}
+			text, what = strings.TrimRight(strings.Join(lines, "\n"), "\n"), "visible text"
+		}
+	default:
+		return m.notify("Yank cancelled")
+	}
+
+	if text == "" {
+		return m.notify("Nothing to yank here")
+	}
+	// A clipboard tool can be slow, so the copy runs outside Update
+	return func() tea.Msg {
+		if err := copyToClipboard(text); err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+		return NotifyMsg("Copied " + what)
+	}
+}
+
+// currentMatch is the text of the current search match on the spec or log
+// screen. The caller must hold the lock.
+func (a *appData) currentMatch(state fsm.State) string {
+	if a.searchPattern == nil || len(a.searchLines) == 0 {
+		return ""
+	}
+
+	var content string
+	switch state {
+	case spec:
+		content = a.specContent()
+	case logs:
+		content = colorizeLog(a.logBuffer)
+	default:
+		return ""
+	}
+
+	// Count matches the way highlightMatches does, skipping empty ones
+	plain := sgrPattern.ReplaceAllString(wordwrap.String(content, a.viewport.Width), "")
+	index := 0
+	for _, line := range strings.Split(plain, "\n") {
+		for _, match := range a.searchPattern.FindAllString(line, -1) {
+			if match == "" {
+				continue
+			}
+			if index == a.searchIndex {
+				return match
+			}
+			index++
+		}
+	}
+	return ""
+}
@@ -16,43 +16,71 @@ var (
 	warnStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("11")).Bold(true) // Yellow
 	errorStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)  // Red
 	debugStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("13")).Bold(true) // Magenta
+
+	diffHeaderStyle = lipgloss.NewStyle().Bold(true)
+	diffHunkStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("14")) // Cyan
+	diffAddStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("10")) // Green
+	diffDelStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("9"))  // Red
 )
 
 func colorizeLog(input string) string {
 	input = strings.ReplaceAll(input, "INFO", infoStyle.Render("INFO"))
-	input = strings.ReplaceAll(input, "WARN", warnStyle.Render("WARN"))
-	input = strings.ReplaceAll(input, "ERROR", errorStyle.Render("ERROR"))
+	input = strings.ReplaceAll(input, "WARN", warnStyle.Render(logLevelMarker("WARN")+"WARN"))
+	input = strings.ReplaceAll(input, "ERROR", errorStyle.Render(logLevelMarker("ERROR")+"ERROR"))
 	input = strings.ReplaceAll(input, "DEBUG", debugStyle.Render("DEBUG"))
 	return input
 }
 
 func highlightYAML(yamlContent string) string {
-	lexer := lexers.Get("yaml")
+	return highlightCode(yamlContent, "yaml")
+}
+
+// highlightCode renders content with the named chroma lexer, falling back to
+// plain text when no such lexer exists.
+func highlightCode(content, lexerName string) string {
+	lexer := lexers.Get(lexerName)
 	if lexer == nil {
 		lexer = lexers.Fallback
 	}
 
-	// You can change "monokai" to "dracula", "solarized-dark", etc.
-	style := styles.Get("monokai")
+	// Both are set by the theme
+	style := styles.Get(chromaStyle)
 	if style == nil {
 		style = styles.Fallback
 	}
 
-	formatter := formatters.Get("terminal256")
+	formatter := formatters.Get(chromaFormatter)
 	if formatter == nil {
 		formatter = formatters.Fallback
 	}
 
-	iterator, err := lexer.Tokenise(nil, yamlContent)
+	iterator, err := lexer.Tokenise(nil, content)
 	if err != nil {
-		return yamlContent
+		return content
 	}
 
 	var buf bytes.Buffer
 	err = formatter.Format(&buf, style, iterator)
 	if err != nil {
-		return yamlContent
+		return content
 	}
 
 	return buf.String()
 }
+
+func colorizeDiff(input string) string {
+	lines := strings.Split(input, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			lines[i] = diffHeaderStyle.Render(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = diffHunkStyle.Render(line)
+		case strings.HasPrefix(line, "+"):
+			lines[i] = diffAddStyle.Render(line)
+		case strings.HasPrefix(line, "-"):
+			lines[i] = diffDelStyle.Render(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// The gutter between the two columns of a side-by-side diff uses sdiff's
+// markers, so changes stand out even without colour.
+const (
+	gutterEqual   = "   "
+	gutterChanged = " | "
+	gutterDeleted = " < "
+	gutterAdded   = " > "
+)
+
+// metadataNoise differs between any two objects without saying anything
+// about how they are configured.
+var metadataNoise = append([]string{"ownerReferences"}, serverMetadata...)
+
+func stripMetadataNoise(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	clean := obj.DeepCopy()
+	for _, field := range metadataNoise {
+		unstructured.RemoveNestedField(clean.Object, "metadata", field)
+	}
+	unstructured.RemoveNestedField(clean.Object, "metadata", "annotations", lastAppliedAnnotation)
+	if annotations := clean.GetAnnotations(); len(annotations) == 0 {
+		unstructured.RemoveNestedField(clean.Object, "metadata", "annotations")
+	}
+	return clean
+}
+
+func resourceName(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return obj.GetName()
+	}
+	return obj.GetNamespace() + "/" + obj.GetName()
+}
+
+// diffRow is one line of a side-by-side diff. A side without a line is left
+// blank.
+type diffRow struct {
+	left, right       string
+	hasLeft, hasRight bool
+	changed           bool
+}
+
+// yamlLines renders a value as indented YAML lines.
+func yamlLines(indent string, value any) []string {
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return []string{indent + "# " + err.Error()}
+	}
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = indent + line
+	}
+	return lines
+}
+
+func equalRows(lines []string) []diffRow {
+	rows := make([]diffRow, len(lines))
+	for i, line := range lines {
+		rows[i] = diffRow{left: line, right: line, hasLeft: true, hasRight: true}
+	}
+	return rows
+}
+
+// changedRows pairs up the lines of a changed value; either side may be
+// empty for a removed or added value.
+func changedRows(from, to []string) []diffRow {
+	rows := make([]diffRow, max(len(from), len(to)))
+	for i := range rows {
+		rows[i].changed = true
+		if i < len(from) {
+			rows[i].left, rows[i].hasLeft = from[i], true
+		}
+		if i < len(to) {
+			rows[i].right, rows[i].hasRight = to[i], true
+		}
+	}
+	return rows
+}
+
+// diffMaps compares two mappings key by key, descending into values that are
+// mappings or lists on both sides.
+func diffMaps(indent string, from, to map[string]any) []diffRow {
+	keys := slices.Collect(maps.Keys(from))
+	for key := range to {
+		if _, ok := from[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var rows []diffRow
+	for _, key := range keys {
+		left, inFrom := from[key]
+		right, inTo := to[key]
+		switch {
+		case !inTo:
+			rows = append(rows, changedRows(yamlLines(indent, map[string]any{key: left}), nil)...)
+		case !inFrom:
+			rows = append(rows, changedRows(nil, yamlLines(indent, map[string]any{key: right}))...)
+		case reflect.DeepEqual(left, right):
+			rows = append(rows, equalRows(yamlLines(indent, map[string]any{key: left}))...)
+		default:
+			nested := diffNested(indent+"    ", left, right)
+			if nested == nil {
+				rows = append(rows, changedRows(
+					yamlLines(indent, map[string]any{key: left}),
+					yamlLines(indent, map[string]any{key: right}))...)
+				continue
+			}
+			rows = append(rows, equalRows([]string{indent + key + ":"})...)
+			rows = append(rows, nested...)
+		}
+	}
+	return rows
+}
+
+// diffNested compares two values of the same container kind, or returns nil
+// when they are scalars or of different kinds.
+func diffNested(indent string, from, to any) []diffRow {
+	switch left := from.(type) {
+	case map[string]any:
+		if right, ok := to.(map[string]any); ok {
+			return diffMaps(indent, left, right)
+		}
+	case []any:
+		if right, ok := to.([]any); ok {
+			return diffLists(indent, left, right)
+		}
+	}
+	return nil
+}
+
+// listItemName is the name of a list item, such as a container, or "" when
+// it has none.
+func listItemName(item any) string {
+	if m, ok := item.(map[string]any); ok {
+		if name, ok := m["name"].(string); ok {
+			return name
+		}
+	}
+	return ""
+}
+
+// diffLists compares two lists item by item, matching items by name when
+// every item has one, so reordering or inserting a container does not show
+// every later one as changed.
+func diffLists(indent string, from, to []any) []diffRow {
+	type pair struct {
+		left, right       any
+		hasLeft, hasRight bool
+	}
+
+	named := true
+	for _, item := range append(slices.Clip(from), to...) {
+		named = named && listItemName(item) != ""
+	}
+
+	var pairs []pair
+	if named {
+		byName := map[string]any{}
+		for _, item := range to {
+			byName[listItemName(item)] = item
+		}
+		seen := map[string]bool{}
+		for _, item := range from {
+			name := listItemName(item)
+			right, ok := byName[name]
+			seen[name] = ok
+			pairs = append(pairs, pair{left: item, right: right, hasLeft: true, hasRight: ok})
+		}
+		for _, item := range to {
+			if !seen[listItemName(item)] {
+				pairs = append(pairs, pair{right: item, hasRight: true})
+			}
+		}
+	} else {
+		for i := 0; i < max(len(from), len(to)); i++ {
+			var p pair
+			if i < len(from) {
+				p.left, p.hasLeft = from[i], true
+			}
+			if i < len(to) {
+				p.right, p.hasRight = to[i], true
+			}
+			pairs = append(pairs, p)
+		}
+	}
+
+	var rows []diffRow
+	for _, p := range pairs {
+		var fromLines, toLines []string
+		if p.hasLeft {
+			fromLines = yamlLines(indent, []any{p.left})
+		}
+		if p.hasRight {
+			toLines = yamlLines(indent, []any{p.right})
+		}
+
+		switch {
+		case !p.hasLeft || !p.hasRight:
+			rows = append(rows, changedRows(fromLines, toLines)...)
+		case reflect.DeepEqual(p.left, p.right):
+			rows = append(rows, equalRows(fromLines)...)
+		default:
+			left, leftMap := p.left.(map[string]any)
+			right, rightMap := p.right.(map[string]any)
+			if !leftMap || !rightMap {
+				rows = append(rows, changedRows(fromLines, toLines)...)
+				continue
+			}
+			// The item's fields sit two columns in, after its "- " marker
+			nested := diffMaps(indent+"  ", left, right)
+			markListItem(nested, indent)
+			rows = append(rows, nested...)
+		}
+	}
+	return rows
+}
+
+// markListItem puts the "- " marker on the first line of each side of a list
+// item.
+func markListItem(rows []diffRow, indent string) {
+	marker := func(line string) string {
+		return indent + "- " + strings.TrimPrefix(line, indent+"  ")
+	}
+	for i := range rows {
+		if rows[i].hasLeft {
+			rows[i].left = marker(rows[i].left)
+			break
+		}
+	}
+	for i := range rows {
+		if rows[i].hasRight {
+			rows[i].right = marker(rows[i].right)
+			break
+		}
+	}
+}
+
+// sideBySideDiff renders two objects field by field in columns of the given
+// total width, colouring removed lines on the left and added lines on the
+// right.
+func sideBySideDiff(from, to map[string]any, width int) string {
+	column := max((width-len(gutterEqual))/2, 10)
+
+	cell := func(line string) string {
+		return runewidth.FillRight(runewidth.Truncate(line, column, "…"), column)
+	}
+
+	var b strings.Builder
+	for _, row := range diffMaps("", from, to) {
+		left, right := cell(row.left), cell(row.right)
+
+		gutter := gutterEqual
+		if row.changed {
+			gutter = gutterChanged
+			if !row.hasRight {
+				gutter = gutterDeleted
+			} else if !row.hasLeft {
+				gutter = gutterAdded
+			}
+			if row.hasLeft {
+				left = diffDelStyle.Render(left)
+			}
+			if row.hasRight {
+				right = diffAddStyle.Render(right)
+			}
+		}
+		b.WriteString(left + gutter + right + "\n")
+	}
+
+	return b.String()
+}
+
+// toggleCompareMark marks the selected object as the left side of the next
+// comparison. The mark survives namespace changes within the same GVR.
+func (m *model) toggleCompareMark() tea.Cmd {
+	obj, ok := m.selectedListResource()
+	if !ok {
+		return nil
+	}
+
+	m.entity.Data.mu.Lock()
+	marked := m.entity.Data.compareMark
+	unmark := marked != nil && marked.GetUID() == obj.GetUID()
+	if unmark {
+		m.entity.Data.compareMark = nil
+	} else {
+		m.entity.Data.compareMark = obj
+	}
+	m.entity.Data.mu.Unlock()
+
+	m.entity.Data.list.Title = m.resourceListTitle()
+	if unmark {
+		return m.notify("Unmarked " + resourceName(obj))
+	}
+	return m.notify("Marked " + resourceName(obj) + ", press d on another object to compare")
+}
+
+// startCompare opens the comparison of the marked object with the selected
+// one in the output viewport.
+func (m *model) startCompare() tea.Cmd {
+	obj, ok := m.selectedListResource()
+	if !ok {
+		return nil
+	}
+
+	m.entity.Data.mu.Lock()
+	marked := m.entity.Data.compareMark
+	if marked != nil {
+		m.entity.Data.compareTarget = obj
+		m.entity.Data.selectedResource = obj
+		m.entity.Data.comparing = true
+	}
+	m.entity.Data.mu.Unlock()
+
+	if marked == nil {
+		return m.notify("Mark an object with m first")
+	}
+
+	m.openOutput("Compare", "Loading...")
+	m.renderCompare()
+	m.entity.Data.outputHelp = "i: ignore metadata noise"
+
+	m.entity.Data.list.ResetFilter()
+	m.entity.Dispatch(transitionScreenForward)
+	return nil
+}
+
+// compareResources renders the marked object against the selected one.
+func (m *model) compareResources() (string, string) {
+	m.entity.Data.mu.RLock()
+	marked := m.entity.Data.compareMark
+	selected := m.entity.Data.compareTarget
+	ignoreNoise := m.entity.Data.compareIgnoreNoise
+	width := m.entity.Data.viewport.Width
+	m.entity.Data.mu.RUnlock()
+
+	if marked == nil || selected == nil {
+		return "Compare", "Mark an object with m, then press d on another."
+	}
+
+	title := fmt.Sprintf("Compare %s with", resourceName(marked))
+	left, right := maskSecretData(marked), maskSecretData(selected)
+	if ignoreNoise {
+		title = fmt.Sprintf("Compare %s without metadata noise with", resourceName(marked))
+		left, right = stripMetadataNoise(left), stripMetadataNoise(right)
+	}
+
+	if reflect.DeepEqual(left.Object, right.Object) {
+		return title, "The two objects are identical."
+	}
+	return title, sideBySideDiff(left.Object, right.Object, width)
+}
+
+func (m *model) toggleCompareNoise() {
+	m.entity.Data.mu.Lock()
+	m.entity.Data.compareIgnoreNoise = !m.entity.Data.compareIgnoreNoise
+	m.entity.Data.mu.Unlock()
+
+	m.renderCompare()
+}
+
+// renderCompare fills the output viewport, which must already be sized, with
+// the comparison.
+func (m *model) renderCompare() {
+	title, content := m.compareResources()
+
+	m.entity.Data.mu.Lock()
+	m.entity.Data.outputTitle = title
+	m.entity.Data.outputBuffer = content
+	m.entity.Data.viewport.SetContent(content)
+	m.entity.Data.mu.Unlock()
+}
+
+// resourceListTitle names the GVR and, once set, the object marked for
+// comparison and the spec mode used for saving.
+func (m *model) resourceListTitle() string {
+	m.entity.Data.mu.RLock()
+	selectedGvr := m.entity.Data.selectedGvr
+	marked := m.entity.Data.compareMark
+	mode := m.entity.Data.specMode
+	m.entity.Data.mu.RUnlock()
+
+	if selectedGvr == nil {
+		return ""
+	}
+
+	title := fmt.Sprintf("Resources (%s)", selectedGvr.Name)
+	if marked != nil {
+		title += " • marked: " + resourceName(marked)
+	}
+	if mode != specFull {
+		title += " • save: " + mode.String()
+	}
+	return title
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSideBySideDiff(t *testing.T) {
+	const width = 63 // two columns of 30
+	container := func(name, image string) map[string]any {
+		return map[string]any{"name": name, "image": image}
+	}
+
+	tests := []struct {
+		name     string
+		from, to map[string]any
+		// left, gutter and right of each row, without padding
+		want [][3]string
+	}{
+		{
+			name: "nested scalar changed",
+			from: map[string]any{"kind": "Pod", "spec": map[string]any{"replicas": int64(1)}},
+			to:   map[string]any{"kind": "Pod", "spec": map[string]any{"replicas": int64(2)}},
+			want: [][3]string{
+				{"kind: Pod", "", "kind: Pod"},
+				{"spec:", "", "spec:"},
+				{"    replicas: 1", "|", "    replicas: 2"},
+			},
+		},
+		{
+			name: "key removed and key added",
+			from: map[string]any{"a": "x"},
+			to:   map[string]any{"b": "z"},
+			want: [][3]string{
+				{"a: x", "<", ""},
+				{"", ">", "b: z"},
+			},
+		},
+		{
+			name: "value changes kind",
+			from: map[string]any{"a": "x"},
+			to:   map[string]any{"a": map[string]any{"b": "z"}},
+			want: [][3]string{
+				{"a: x", "|", "a:"},
+				{"", ">", "    b: z"},
+			},
+		},
+		{
+			name: "list items matched by name",
+			from: map[string]any{"c": []any{container("app", "v1"), container("side", "v1")}},
+			to:   map[string]any{"c": []any{container("side", "v1"), container("app", "v2")}},
+			want: [][3]string{
+				{"c:", "", "c:"},
+				{"    - image: v1", "|", "    - image: v2"},
+				{"      name: app", "", "      name: app"},
+				{"    - image: v1", "", "    - image: v1"},
+				{"      name: side", "", "      name: side"},
+			},
+		},
+		{
+			name: "unnamed list items matched by index",
+			from: map[string]any{"args": []any{"a", "b"}},
+			to:   map[string]any{"args": []any{"a", "c", "d"}},
+			want: [][3]string{
+				{"args:", "", "args:"},
+				{"    - a", "", "    - a"},
+				{"    - b", "|", "    - c"},
+				{"", ">", "    - d"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sgrPattern.ReplaceAllString(sideBySideDiff(tt.from, tt.to, width), "")
+			lines := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+			if len(lines) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d:\n%s", len(lines), len(tt.want), got)
+			}
+
+			column := (width - len(gutterEqual)) / 2
+			for i, line := range lines {
+				row := [3]string{
+					strings.TrimRight(line[:column], " "),
+					strings.TrimSpace(line[column : column+len(gutterEqual)]),
+					strings.TrimRight(line[column+len(gutterEqual):], " "),
+				}
+				if row != tt.want[i] {
+					t.Errorf("row %d = %q, want %q", i, row, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCompareRelaidOutOnResize(t *testing.T) {
+	object := func(name, image string) *unstructured.Unstructured {
+		return &unstructured.Unstructured{Object: map[string]any{
+			"apiVersion": "v1",
+			"kind":       "Pod",
+			"metadata":   map[string]any{"name": name, "namespace": "default"},
+			"spec": map[string]any{
+				"containers": []any{map[string]any{"name": "app", "image": image}},
+			},
+		}}
+	}
+	marked, selected := object("a", "app:v1"), object("b", "app:v2")
+
+	d := newAppData()
+	d.list = initializeGvrList([]list.Item{item(resourceName(selected))})
+	d.unstructured = []*unstructured.Unstructured{marked, selected}
+	d.compareMark = marked
+	m := newModel(d)
+	m.entity.SetInitialState(resource)
+	m.Update(tea.WindowSizeMsg{Width: 80, Height: 30})
+
+	m.startCompare()
+	if state := m.entity.GetCurrentState(); state != output {
+		t.Fatalf("state = %v after compare, want output", state)
+	}
+
+	for _, width := range []int{120, 60} {
+		m.Update(tea.WindowSizeMsg{Width: width, Height: 30})
+		want := sideBySideDiff(marked.Object, selected.Object, width)
+		if d.outputBuffer != want {
+			t.Errorf("after resizing to %d the comparison is\n%s\nwant\n%s", width, d.outputBuffer, want)
+		}
+	}
+
+	m.entity.Dispatch(transitionScreenBackward)
+	if d.comparing {
+		t.Error("comparing is still set after leaving the comparison")
+	}
+	if state := m.entity.GetCurrentState(); state != resource {
+		t.Errorf("state = %v after going back, want resource", state)
+	}
+}
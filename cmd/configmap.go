@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2/lexers"
+	"github.com/alexei-ozerov/kube-traverse/internal/kube"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// extensionLexers covers extensions chroma cannot place from the key alone.
+var extensionLexers = map[string]string{
+	".conf": "nginx",
+	".env":  "bash",
+}
+
+func isConfigMap(g *kube.ApiResource) bool {
+	return g != nil && g.GVR.Group == "" && g.GVR.Resource == "configmaps"
+}
+
+func configMapEntries(obj *unstructured.Unstructured) []dataEntry {
+	data, _, _ := unstructured.NestedStringMap(obj.Object, "data")
+	binaryData, _, _ := unstructured.NestedStringMap(obj.Object, "binaryData")
+
+	entries := make([]dataEntry, 0, len(data)+len(binaryData))
+	for key, value := range data {
+		entries = append(entries, dataEntry{key: key, value: []byte(value)})
+	}
+	for key, encoded := range binaryData {
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			value = []byte(encoded)
+		}
+		entries = append(entries, dataEntry{key: key, value: value, binary: true})
+	}
+
+	slices.SortFunc(entries, func(a, b dataEntry) int { return strings.Compare(a.key, b.key) })
+	return entries
+}
+
+// lexerForKey guesses a chroma lexer from the key, treating it as a file
+// name, and falls back to sniffing the content.
+func lexerForKey(key, content string) string {
+	if lexer := lexers.Match(key); lexer != nil {
+		return lexer.Config().Name
+	}
+	if name, ok := extensionLexers[strings.ToLower(path.Ext(key))]; ok {
+		return name
+	}
+	if lexer := lexers.Analyse(content); lexer != nil {
+		return lexer.Config().Name
+	}
+	return "plaintext"
+}
+
+func renderConfigMapValue(entry dataEntry) string {
+	if entry.binary {
+		return hex.Dump(entry.value)
+	}
+	return highlightCode(string(entry.value), lexerForKey(entry.key, string(entry.value)))
+}
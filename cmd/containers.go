@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// containerLabel names a container in the picker, adding its restart count
+// and how its previous instance ended, if it has one.
+func containerLabel(name string, status map[string]any) string {
+	restarts, _, _ := unstructured.NestedInt64(status, "restartCount")
+	terminated, found, _ := unstructured.NestedMap(status, "lastState", "terminated")
+	if restarts == 0 && !found {
+		return name
+	}
+
+	details := []string{fmt.Sprintf("restarts: %d", restarts)}
+	if found {
+		reason, _, _ := unstructured.NestedString(terminated, "reason")
+		exitCode, _, _ := unstructured.NestedInt64(terminated, "exitCode")
+		if reason == "" {
+			reason = "Terminated"
+		}
+		details = append(details, fmt.Sprintf("last: %s, exit code %d", reason, exitCode))
+	}
+	return fmt.Sprintf("%s (%s)", name, strings.Join(details, "; "))
+}
+
+// containerFromItem recovers the container name from a picker label.
+// Container names cannot contain spaces.
+func containerFromItem(label string) string {
+	name, _, _ := strings.Cut(label, " ")
+	return name
+}
+
+// containerItems lists a pod's containers, then its init containers, with
+// their status.
+func containerItems(pod *unstructured.Unstructured) []string {
+	statuses := map[string]map[string]any{}
+	for _, field := range []string{"containerStatuses", "initContainerStatuses"} {
+		list, _, _ := unstructured.NestedSlice(pod.Object, "status", field)
+		for _, s := range list {
+			if status, ok := s.(map[string]any); ok {
+				if name, ok := status["name"].(string); ok {
+					statuses[name] = status
+				}
+			}
+		}
+	}
+
+	containers, _, _ := unstructured.NestedSlice(pod.Object, "spec", "containers")
+	initContainers, _, _ := unstructured.NestedSlice(pod.Object, "spec", "initContainers")
+
+	var labels []string
+	for _, c := range append(containers, initContainers...) {
+		if cMap, ok := c.(map[string]any); ok {
+			if name, ok := cMap["name"].(string); ok {
+				labels = append(labels, containerLabel(name, statuses[name]))
+			}
+		}
+	}
+	return labels
+}
+
+// togglePreviousLogs switches the log screen between the running container
+// and its previous, terminated instance.
+func (m *model) togglePreviousLogs() tea.Cmd {
+	m.entity.Data.mu.Lock()
+	if m.entity.Data.cancelLog != nil {
+		m.entity.Data.cancelLog()
+	}
+	m.entity.Data.previousLogs = !m.entity.Data.previousLogs
+	m.entity.Data.logBuffer = ""
+	m.entity.Data.setViewportContent("")
+	m.entity.Data.mu.Unlock()
+
+	m.clearSearch()
+	return m.startLiveLogs()
+}
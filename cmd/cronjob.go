@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/alexei-ozerov/kube-traverse/internal/kube"
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	instantiateAnnotation = "cronjob.kubernetes.io/instantiate"
+	jobNameLabel          = "job-name"
+	jobPodTimeout         = 2 * time.Minute
+	// Job names end up in the job-name label, which is capped at 63 characters.
+	maxJobNameLength = 63
+)
+
+var jobGVR = schema.GroupVersionResource{Group: "batch", Version: "v1", Resource: "jobs"}
+
+// JobPodMsg carries the first pod of a manually triggered Job once its
+// containers have started, so the TUI can jump to its logs.
+type JobPodMsg struct {
+	job string
+	pod *unstructured.Unstructured
+}
+
+func isCronJob(g *kube.ApiResource) bool {
+	return g != nil && g.GVR.Group == "batch" && g.GVR.Resource == "cronjobs"
+}
+
+func (m *model) toggleSuspend() tea.Cmd {
+	return func() tea.Msg {
+		cronJob := m.refreshSelectedResource()
+
+		m.entity.Data.mu.RLock()
+		selectedGvr := m.entity.Data.selectedGvr
+		dynClient := m.entity.Data.clients.Dynamic
+		m.entity.Data.mu.RUnlock()
+
+		if cronJob == nil || selectedGvr == nil || dynClient == nil {
+			return NotifyMsg("Error: Missing CronJob or Client")
+		}
+
+		suspended, _, _ := unstructured.NestedBool(cronJob.Object, "spec", "suspend")
+		patch, err := json.Marshal(map[string]any{
+			"spec": map[string]any{"suspend": !suspended},
+		})
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		_, err = dynClient.Client.Resource(selectedGvr.GVR).
+			Namespace(cronJob.GetNamespace()).
+			Patch(context.Background(), cronJob.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		if suspended {
+			return NotifyMsg("Resumed: " + cronJob.GetName())
+		}
+		return NotifyMsg("Suspended: " + cronJob.GetName())
+	}
+}
+
+// triggerCronJob creates a Job from the CronJob's jobTemplate the way
+// `kubectl create job --from=cronjob/...` does, then waits for its pod.
+func (m *model) triggerCronJob() tea.Cmd {
+	return func() tea.Msg {
+		cronJob := m.refreshSelectedResource()
+
+		m.entity.Data.mu.RLock()
+		dynClient := m.entity.Data.clients.Dynamic
+		clientset := m.entity.Data.clients.Typed
+		m.entity.Data.mu.RUnlock()
+
+		if cronJob == nil || dynClient == nil || clientset == nil {
+			return NotifyMsg("Error: Missing CronJob or Client")
+		}
+
+		template, found, _ := unstructured.NestedMap(cronJob.Object, "spec", "jobTemplate")
+		if !found {
+			return NotifyMsg("Error: " + cronJob.GetName() + " has no jobTemplate")
+		}
+
+		suffix := "-manual-" + utilrand.String(5)
+		base := cronJob.GetName()
+		if len(base)+len(suffix) > maxJobNameLength {
+			base = base[:maxJobNameLength-len(suffix)]
+		}
+
+		job := &unstructured.Unstructured{Object: map[string]any{}}
+		job.SetAPIVersion("batch/v1")
+		job.SetKind("Job")
+		job.SetName(base + suffix)
+		job.SetNamespace(cronJob.GetNamespace())
+
+		labels, _, _ := unstructured.NestedStringMap(template, "metadata", "labels")
+		annotations, _, _ := unstructured.NestedStringMap(template, "metadata", "annotations")
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		annotations[instantiateAnnotation] = "manual"
+		job.SetLabels(labels)
+		job.SetAnnotations(annotations)
+
+		job.SetOwnerReferences([]metav1.OwnerReference{{
+			APIVersion:         "batch/v1",
+			Kind:               "CronJob",
+			Name:               cronJob.GetName(),
+			UID:                cronJob.GetUID(),
+			Controller:         ptr.To(true),
+			BlockOwnerDeletion: ptr.To(true),
+		}})
+
+		spec, _, _ := unstructured.NestedMap(template, "spec")
+		if err := unstructured.SetNestedMap(job.Object, spec, "spec"); err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		ctx := context.Background()
+		created, err := dynClient.Client.Resource(jobGVR).
+			Namespace(job.GetNamespace()).
+			Create(ctx, job, metav1.CreateOptions{})
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		m.entity.Data.program.Send(NotifyMsg("Created job " + created.GetName() + ", waiting for its pod..."))
+
+		var pod *corev1.Pod
+		err = wait.PollUntilContextTimeout(ctx, time.Second, jobPodTimeout, true, func(ctx context.Context) (bool, error) {
+			pods, err := clientset.CoreV1().Pods(created.GetNamespace()).List(ctx, metav1.ListOptions{
+				LabelSelector: jobNameLabel + "=" + created.GetName(),
+			})
+			if err != nil {
+				return false, err
+			}
+			for i := range pods.Items {
+				if pods.Items[i].Status.Phase != corev1.PodPending {
+					pod = &pods.Items[i]
+					return true, nil
+				}
+			}
+			return false, nil
+		})
+		if err != nil {
+			return NotifyMsg("Error waiting for " + created.GetName() + ": " + err.Error())
+		}
+
+		obj, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pod)
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		return JobPodMsg{job: created.GetName(), pod: &unstructured.Unstructured{Object: obj}}
+	}
+}
+
+// followJobPod swaps the selection over to the triggered Job's pod and opens
+// the container picker for its logs. The CronJob is restored on the way back.
+func (m *model) followJobPod(msg JobPodMsg) {
+	if m.entity.GetCurrentState() != action || m.entity.Data.choice != "run-now*" {
+		return
+	}
+
+	m.entity.Data.mu.Lock()
+	m.entity.Data.returnResource = m.entity.Data.selectedResource
+	m.entity.Data.selectedResource = msg.pod
+	m.entity.Data.mu.Unlock()
+
+	m.entity.Dispatch(transitionScreenForward)
+	m.syncList()
+}
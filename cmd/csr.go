@@ -0,0 +1,195 @@
+package main
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	certificatesv1 "k8s.io/api/certificates/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+func (m *model) showCSR() tea.Cmd {
+	generation := m.outputGeneration()
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		selectedResource := m.entity.Data.selectedResource
+		clientset := m.entity.Data.clients.Typed
+		m.entity.Data.mu.RUnlock()
+
+		if selectedResource == nil || clientset == nil {
+			return OutputMsg{generation: generation, text: "Error: Missing CSR or Client"}
+		}
+
+		csr, err := clientset.CertificatesV1().CertificateSigningRequests().
+			Get(context.Background(), selectedResource.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return OutputMsg{generation: generation, text: "Error: " + err.Error()}
+		}
+
+		return OutputMsg{generation: generation, text: describeCSR(csr)}
+	}
+}
+
+// describeCSR renders the CSR's metadata alongside the decoded PEM request.
+func describeCSR(csr *certificatesv1.CertificateSigningRequest) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(w, "Name:\t%s\n", csr.Name)
+	fmt.Fprintf(w, "Status:\t%s\n", csrStatus(csr))
+	fmt.Fprintf(w, "Signer:\t%s\n", csr.Spec.SignerName)
+	fmt.Fprintf(w, "Requestor:\t%s\n", csr.Spec.Username)
+	fmt.Fprintf(w, "Groups:\t%s\n", strings.Join(csr.Spec.Groups, ", "))
+
+	usages := make([]string, 0, len(csr.Spec.Usages))
+	for _, usage := range csr.Spec.Usages {
+		usages = append(usages, string(usage))
+	}
+	fmt.Fprintf(w, "Key Usages:\t%s\n", strings.Join(usages, ", "))
+
+	if csr.Spec.ExpirationSeconds != nil {
+		fmt.Fprintf(w, "Requested Duration:\t%s\n", time.Duration(*csr.Spec.ExpirationSeconds)*time.Second)
+	}
+
+	block, _ := pem.Decode(csr.Spec.Request)
+	if block == nil {
+		fmt.Fprintf(w, "\nRequest:\tno PEM block found\n")
+		w.Flush()
+		return b.String()
+	}
+
+	req, err := x509.ParseCertificateRequest(block.Bytes)
+	if err != nil {
+		fmt.Fprintf(w, "\nRequest:\t%s\n", err.Error())
+		w.Flush()
+		return b.String()
+	}
+
+	fmt.Fprintf(w, "\nSubject:\t%s\n", req.Subject.String())
+	fmt.Fprintf(w, "DNS SANs:\t%s\n", strings.Join(req.DNSNames, ", "))
+
+	ips := make([]string, 0, len(req.IPAddresses))
+	for _, ip := range req.IPAddresses {
+		ips = append(ips, ip.String())
+	}
+	fmt.Fprintf(w, "IP SANs:\t%s\n", strings.Join(ips, ", "))
+	fmt.Fprintf(w, "Email SANs:\t%s\n", strings.Join(req.EmailAddresses, ", "))
+
+	uris := make([]string, 0, len(req.URIs))
+	for _, uri := range req.URIs {
+		uris = append(uris, uri.String())
+	}
+	fmt.Fprintf(w, "URI SANs:\t%s\n", strings.Join(uris, ", "))
+	fmt.Fprintf(w, "Public Key:\t%s\n", describePublicKey(req.PublicKey))
+	fmt.Fprintf(w, "Signature:\t%s\n", req.SignatureAlgorithm.String())
+
+	if len(csr.Status.Conditions) > 0 {
+		fmt.Fprintf(w, "\nConditions:\n")
+		for _, cond := range csr.Status.Conditions {
+			fmt.Fprintf(w, "  %s\t%s: %s\n", cond.Type, cond.Reason, cond.Message)
+		}
+	}
+
+	w.Flush()
+	return b.String()
+}
+
+func csrStatus(csr *certificatesv1.CertificateSigningRequest) string {
+	var states []string
+	for _, cond := range csr.Status.Conditions {
+		if cond.Status == corev1.ConditionTrue {
+			states = append(states, string(cond.Type))
+		}
+	}
+	if len(csr.Status.Certificate) > 0 {
+		states = append(states, "Issued")
+	}
+	if len(states) == 0 {
+		return "Pending"
+	}
+	return strings.Join(states, ", ")
+}
+
+func describePublicKey(key any) string {
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		return fmt.Sprintf("RSA %d bits", k.N.BitLen())
+	case *ecdsa.PublicKey:
+		return "ECDSA " + k.Curve.Params().Name
+	case ed25519.PublicKey:
+		return "Ed25519"
+	default:
+		return fmt.Sprintf("%T", key)
+	}
+}
+
+func (m *model) promptCSRDecision(approve bool) {
+	label := "Deny reason"
+	if approve {
+		label = "Approve reason"
+	}
+
+	m.requestInput(label, "", func(reason string) tea.Cmd {
+		return m.decideCSR(approve, reason)
+	})
+}
+
+// decideCSR adds an Approved or Denied condition through the approval
+// subresource, as `kubectl certificate approve|deny` does.
+func (m *model) decideCSR(approve bool, message string) tea.Cmd {
+	generation := m.outputGeneration()
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		selectedResource := m.entity.Data.selectedResource
+		clientset := m.entity.Data.clients.Typed
+		m.entity.Data.mu.RUnlock()
+
+		if selectedResource == nil || clientset == nil {
+			return NotifyMsg("Error: Missing CSR or Client")
+		}
+
+		csr, err := updateCSRApproval(context.Background(), clientset, selectedResource.GetName(), approve, message)
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		return OutputMsg{generation: generation, text: describeCSR(csr)}
+	}
+}
+
+func updateCSRApproval(ctx context.Context, clientset kubernetes.Interface, name string, approve bool, message string) (*certificatesv1.CertificateSigningRequest, error) {
+	csrs := clientset.CertificatesV1().CertificateSigningRequests()
+	csr, err := csrs.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	condition := certificatesv1.CertificateSigningRequestCondition{
+		Type:           certificatesv1.CertificateDenied,
+		Status:         corev1.ConditionTrue,
+		Reason:         "KubeTraverseDeny",
+		Message:        message,
+		LastUpdateTime: metav1.Now(),
+	}
+	if approve {
+		condition.Type = certificatesv1.CertificateApproved
+		condition.Reason = "KubeTraverseApprove"
+	}
+	if condition.Message == "" {
+		condition.Message = "This CSR was " + strings.ToLower(string(condition.Type)) + " from kube-traverse."
+	}
+
+	csr.Status.Conditions = append(csr.Status.Conditions, condition)
+	return csrs.UpdateApproval(ctx, name, csr, metav1.UpdateOptions{})
+}
@@ -0,0 +1,218 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/alexei-ozerov/kube-traverse/internal/kube"
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	defaultDebugImage   = "busybox"
+	nodeDebugNamespace  = "default"
+	debugStartupTimeout = 2 * time.Minute
+)
+
+func (m *model) promptDebugContainer() {
+	m.requestInput("Debug image", defaultDebugImage, func(image string) tea.Cmd {
+		m.requestInput("Target container (optional)", "", func(target string) tea.Cmd {
+			return tea.Batch(
+				m.notify("Starting debug container..."),
+				m.startDebugContainer(image, target),
+			)
+		})
+		return nil
+	})
+}
+
+func (m *model) promptNodeDebug() {
+	m.requestInput("Debug image", defaultDebugImage, func(image string) tea.Cmd {
+		return tea.Batch(
+			m.notify("Starting node debug pod..."),
+			m.startNodeDebugPod(image),
+		)
+	})
+}
+
+// startDebugContainer patches an ephemeral container into the selected pod,
+// optionally sharing the target container's process namespace, and attaches to
+// it once it is running.
+func (m *model) startDebugContainer(image, target string) tea.Cmd {
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		pod := m.entity.Data.selectedResource
+		clientset := m.entity.Data.clients.Typed
+		m.entity.Data.mu.RUnlock()
+
+		if pod == nil || clientset == nil {
+			return NotifyMsg("Error: Missing Pod or Client")
+		}
+
+		name := "debugger-" + utilrand.String(5)
+		patch, err := json.Marshal(map[string]any{
+			"spec": map[string]any{
+				"ephemeralContainers": []corev1.EphemeralContainer{{
+					EphemeralContainerCommon: corev1.EphemeralContainerCommon{
+						Name:                     name,
+						Image:                    image,
+						Stdin:                    true,
+						TTY:                      true,
+						TerminationMessagePolicy: corev1.TerminationMessageReadFile,
+					},
+					TargetContainerName: target,
+				}},
+			},
+		})
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		ctx := context.Background()
+		_, err = clientset.CoreV1().Pods(pod.GetNamespace()).
+			Patch(ctx, pod.GetName(), types.StrategicMergePatchType, patch, metav1.PatchOptions{}, "ephemeralcontainers")
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		if err := waitForContainer(ctx, clientset, pod.GetNamespace(), pod.GetName(), name); err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		return SessionMsg{opts: kube.StreamOptions{
+			Namespace: pod.GetNamespace(),
+			Pod:       pod.GetName(),
+			Container: name,
+			Stdin:     true,
+			TTY:       true,
+		}}
+	}
+}
+
+// startNodeDebugPod runs a privileged pod sharing the node's PID, network and
+// IPC namespaces with the host filesystem mounted at /host, then execs into it.
+// The pod is deleted when the session ends.
+func (m *model) startNodeDebugPod(image string) tea.Cmd {
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		node := m.entity.Data.selectedResource
+		clientset := m.entity.Data.clients.Typed
+		m.entity.Data.mu.RUnlock()
+
+		if node == nil || clientset == nil {
+			return NotifyMsg("Error: Missing Node or Client")
+		}
+
+		pod := &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("node-debugger-%s-%s", node.GetName(), utilrand.String(5)),
+				Namespace: nodeDebugNamespace,
+			},
+			Spec: corev1.PodSpec{
+				NodeName:      node.GetName(),
+				HostPID:       true,
+				HostNetwork:   true,
+				HostIPC:       true,
+				RestartPolicy: corev1.RestartPolicyNever,
+				Tolerations:   []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+				Containers: []corev1.Container{{
+					Name:            "debugger",
+					Image:           image,
+					Command:         []string{"sh", "-c", "sleep 3600"},
+					SecurityContext: &corev1.SecurityContext{Privileged: ptr.To(true)},
+					VolumeMounts:    []corev1.VolumeMount{{Name: "host-root", MountPath: "/host"}},
+				}},
+				Volumes: []corev1.Volume{{
+					Name: "host-root",
+					VolumeSource: corev1.VolumeSource{
+						HostPath: &corev1.HostPathVolumeSource{Path: "/"},
+					},
+				}},
+			},
+		}
+
+		ctx := context.Background()
+		created, err := clientset.CoreV1().Pods(nodeDebugNamespace).Create(ctx, pod, metav1.CreateOptions{})
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		cleanup := deleteDebugPod(clientset, created.Namespace, created.Name)
+		if err := waitForContainer(ctx, clientset, created.Namespace, created.Name, "debugger"); err != nil {
+			cleanup()
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		return SessionMsg{
+			opts: kube.StreamOptions{
+				Namespace: created.Namespace,
+				Pod:       created.Name,
+				Container: "debugger",
+				Command:   []string{"sh"},
+				Stdin:     true,
+				TTY:       true,
+			},
+			cleanup: cleanup,
+		}
+	}
+}
+
+func deleteDebugPod(clientset kubernetes.Interface, namespace, name string) tea.Cmd {
+	return func() tea.Msg {
+		err := clientset.CoreV1().Pods(namespace).Delete(context.Background(), name, metav1.DeleteOptions{
+			GracePeriodSeconds: ptr.To[int64](0),
+		})
+		if err != nil {
+			return NotifyMsg("Error cleaning up " + name + ": " + err.Error())
+		}
+		return NotifyMsg("Deleted debug pod " + name)
+	}
+}
+
+// stuckWaitingReasons are the waiting reasons a container does not recover
+// from without a change to its spec.
+var stuckWaitingReasons = []string{
+	"ErrImagePull",
+	"ImagePullBackOff",
+	"InvalidImageName",
+	"CreateContainerConfigError",
+	"CreateContainerError",
+	"RunContainerError",
+}
+
+// waitForContainer polls until the named container or ephemeral container is
+// running, failing early if it terminates first or is stuck waiting, e.g.
+// for an image that cannot be pulled.
+func waitForContainer(ctx context.Context, clientset kubernetes.Interface, namespace, pod, container string) error {
+	return wait.PollUntilContextTimeout(ctx, time.Second, debugStartupTimeout, true, func(ctx context.Context) (bool, error) {
+		p, err := clientset.CoreV1().Pods(namespace).Get(ctx, pod, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+
+		statuses := append(p.Status.ContainerStatuses, p.Status.EphemeralContainerStatuses...)
+		for _, status := range statuses {
+			if status.Name != container {
+				continue
+			}
+			if status.State.Terminated != nil {
+				return false, fmt.Errorf("container %s terminated: %s", container, status.State.Terminated.Reason)
+			}
+			if waiting := status.State.Waiting; waiting != nil && slices.Contains(stuckWaitingReasons, waiting.Reason) {
+				return false, fmt.Errorf("container %s is waiting: %s: %s", container, waiting.Reason, waiting.Message)
+			}
+			return status.State.Running != nil, nil
+		}
+		return false, nil
+	})
+}
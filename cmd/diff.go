@@ -0,0 +1,27 @@
+package main
+
+import (
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// unifiedDiff renders a coloured unified diff between two texts. An empty
+// string means the inputs are identical.
+func unifiedDiff(from, to, fromName, toName string) string {
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(from),
+		B:        difflib.SplitLines(to),
+		FromFile: fromName,
+		ToFile:   toName,
+		Context:  3,
+	}
+
+	text, err := difflib.GetUnifiedDiffString(diff)
+	if err != nil {
+		return "Error computing diff: " + err.Error()
+	}
+	if text == "" {
+		return ""
+	}
+
+	return colorizeDiff(text)
+}
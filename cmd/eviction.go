@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	policyv1 "k8s.io/api/policy/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+)
+
+// errPodGone is returned by evictPod when there was no pod left to evict.
+var errPodGone = errors.New("pod is already gone")
+
+// evictPod submits a single Eviction for the pod. A pod that is already gone
+// gives errPodGone; a 429 means a PodDisruptionBudget is blocking it.
+func evictPod(ctx context.Context, clientset kubernetes.Interface, namespace, name string) error {
+	eviction := &policyv1.Eviction{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	err := clientset.CoreV1().Pods(namespace).EvictV1(ctx, eviction)
+	if apierrors.IsNotFound(err) {
+		return errPodGone
+	}
+	return err
+}
+
+// evictionFailureReason extracts the API server's explanation, including the
+// per-budget causes it attaches when a PodDisruptionBudget blocks an eviction.
+func evictionFailureReason(err error) string {
+	var apiStatus apierrors.APIStatus
+	if !errors.As(err, &apiStatus) {
+		return err.Error()
+	}
+
+	status := apiStatus.Status()
+	reason := status.Message
+	if status.Details != nil {
+		for _, cause := range status.Details.Causes {
+			reason += "\n    " + cause.Message
+		}
+	}
+	return reason
+}
+
+// matchingBudgetsReport lists the PodDisruptionBudgets in the pod's namespace
+// whose selector matches it, with how many disruptions each still allows.
+func matchingBudgetsReport(ctx context.Context, clientset kubernetes.Interface, namespace, name string, podLabels map[string]string) (string, error) {
+	pdbs, err := clientset.PolicyV1().PodDisruptionBudgets(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	var b strings.Builder
+	for _, pdb := range pdbs.Items {
+		selector, err := metav1.LabelSelectorAsSelector(pdb.Spec.Selector)
+		if err != nil || !selector.Matches(labels.Set(podLabels)) {
+			continue
+		}
+
+		budget := "-"
+		if pdb.Spec.MinAvailable != nil {
+			budget = "minAvailable " + pdb.Spec.MinAvailable.String()
+		} else if pdb.Spec.MaxUnavailable != nil {
+			budget = "maxUnavailable " + pdb.Spec.MaxUnavailable.String()
+		}
+
+		fmt.Fprintf(&b, "  %s  %s  disruptionsAllowed %d  healthy %d/%d\n",
+			pdb.Name, budget,
+			pdb.Status.DisruptionsAllowed,
+			pdb.Status.CurrentHealthy, pdb.Status.DesiredHealthy)
+	}
+
+	if b.Len() == 0 {
+		return fmt.Sprintf("No PodDisruptionBudgets match %s/%s.\n", namespace, name), nil
+	}
+	return fmt.Sprintf("PodDisruptionBudgets matching %s/%s:\n\n%s", namespace, name, b.String()), nil
+}
+
+func (m *model) planEviction() tea.Cmd {
+	generation := m.outputGeneration()
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		pod := m.entity.Data.selectedResource
+		clientset := m.entity.Data.clients.Typed
+		m.entity.Data.mu.RUnlock()
+
+		if pod == nil || clientset == nil {
+			return OutputMsg{generation: generation, text: "Error: Missing Pod or Client"}
+		}
+
+		report, err := matchingBudgetsReport(context.Background(), clientset, pod.GetNamespace(), pod.GetName(), pod.GetLabels())
+		if err != nil {
+			return OutputMsg{generation: generation, text: "Error listing PodDisruptionBudgets: " + err.Error()}
+		}
+
+		return PlanMsg{
+			generation: generation,
+			text:       report,
+			prompt:     fmt.Sprintf("Evict pod %s/%s?", pod.GetNamespace(), pod.GetName()),
+			cmd:        m.submitEviction(),
+		}
+	}
+}
+
+func (m *model) submitEviction() tea.Cmd {
+	generation := m.outputGeneration()
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		pod := m.entity.Data.selectedResource
+		clientset := m.entity.Data.clients.Typed
+		m.entity.Data.mu.RUnlock()
+
+		if pod == nil || clientset == nil {
+			return OutputChunkMsg{generation: generation, text: "\nError: Missing Pod or Client\n"}
+		}
+
+		err := evictPod(context.Background(), clientset, pod.GetNamespace(), pod.GetName())
+		switch {
+		case err == nil:
+			return OutputChunkMsg{generation: generation, text: fmt.Sprintf("\nEviction of %s accepted.\n", pod.GetName())}
+		case errors.Is(err, errPodGone):
+			return OutputChunkMsg{generation: generation, text: fmt.Sprintf("\nPod %s is already gone, nothing to evict.\n", pod.GetName())}
+		case apierrors.IsTooManyRequests(err):
+			return OutputChunkMsg{generation: generation, text: "\nEviction blocked: " + evictionFailureReason(err) + "\n"}
+		default:
+			return OutputChunkMsg{generation: generation, text: "\nEviction failed: " + evictionFailureReason(err) + "\n"}
+		}
+	}
+}
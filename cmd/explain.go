@@ -0,0 +1,210 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/alexei-ozerov/kube-traverse/internal/kube"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/reflow/wordwrap"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+var (
+	explainPanelStyle = lipgloss.NewStyle().
+				Border(lipgloss.NormalBorder(), false, false, false, true).
+				PaddingLeft(1)
+	explainRequiredStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("9")).Bold(true)
+)
+
+// SchemaMsg delivers the OpenAPI document of a group version, or the error
+// fetching it.
+type SchemaMsg struct {
+	gv  schema.GroupVersion
+	doc *kube.SchemaDoc
+	err error
+}
+
+func explainPanelWidth(total int) int {
+	return min(max(total/3, 30), total/2)
+}
+
+// specViewportWidth is the width left for the spec out of the terminal
+// width. The caller must hold the lock.
+func (a *appData) specViewportWidth(total int) int {
+	if a.explainMode {
+		return total - explainPanelWidth(total)
+	}
+	return total
+}
+
+// fetchSchema loads the schema of the selected object's group version unless
+// the explain panel is hidden or the schema is already loaded.
+func (m *model) fetchSchema() tea.Cmd {
+	m.entity.Data.mu.RLock()
+	explainMode := m.entity.Data.explainMode
+	selectedResource := m.entity.Data.selectedResource
+	m.entity.Data.mu.RUnlock()
+
+	if !explainMode || selectedResource == nil {
+		return nil
+	}
+
+	gv := selectedResource.GroupVersionKind().GroupVersion()
+	m.entity.Data.mu.RLock()
+	result, loaded := m.entity.Data.schemas[gv]
+	m.entity.Data.mu.RUnlock()
+	if loaded && result.err == nil {
+		return nil
+	}
+
+	return func() tea.Msg {
+		doc, err := m.entity.Data.clients.Discovery.GetSchema(gv)
+		return SchemaMsg{gv: gv, doc: doc, err: err}
+	}
+}
+
+// storeSchema keeps a fetched schema, or the error, for the session.
+func (a *appData) storeSchema(msg SchemaMsg) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.schemas == nil {
+		a.schemas = map[schema.GroupVersion]SchemaMsg{}
+	}
+	a.schemas[msg.gv] = msg
+}
+
+// toggleExplain shows or hides the field documentation panel beside the
+// spec.
+func (m *model) toggleExplain() tea.Cmd {
+	total := m.entity.Data.list.Width()
+
+	m.entity.Data.mu.Lock()
+	m.entity.Data.explainMode = !m.entity.Data.explainMode
+	m.entity.Data.viewport.Width = m.entity.Data.specViewportWidth(total)
+	m.entity.Data.mu.Unlock()
+
+	m.syncSpec()
+	return m.fetchSchema()
+}
+
+// parseSpec keeps the plain lines of the rendered spec and the document they
+// parse to while the explain panel is shown, so it is not parsed again on
+// every frame. The caller must hold the lock.
+func (a *appData) parseSpec(content string) {
+	a.specLines, a.specNode = nil, nil
+	if !a.explainMode {
+		return
+	}
+
+	plain := sgrPattern.ReplaceAllString(content, "")
+	// JSON is YAML too, so this covers both formats
+	var root yaml.Node
+	if err := yaml.Unmarshal([]byte(plain), &root); err != nil {
+		return
+	}
+	a.specLines = strings.Split(plain, "\n")
+	a.specNode = &root
+}
+
+// specLineFields finds the field on a line of the wrapped spec viewport by
+// mapping the line back to the document it was rendered from.
+func specLineFields(lines []string, root *yaml.Node, width, top int) []string {
+	if root == nil {
+		return nil
+	}
+
+	line, wrapped := 0, 0
+	for line < len(lines)-1 {
+		wrapped += strings.Count(wordwrap.String(lines[line], width), "\n") + 1
+		if wrapped > top {
+			break
+		}
+		line++
+	}
+	return nodeFields(root, line+1, nil)
+}
+
+// nodeFields walks down to the deepest node starting at or before line.
+func nodeFields(n *yaml.Node, line int, fields []string) []string {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) > 0 {
+			return nodeFields(n.Content[0], line, fields)
+		}
+	case yaml.MappingNode:
+		for i := len(n.Content) - 2; i >= 0; i -= 2 {
+			if key := n.Content[i]; key.Line <= line {
+				return nodeFields(n.Content[i+1], line, append(fields, key.Value))
+			}
+		}
+	case yaml.SequenceNode:
+		for i := len(n.Content) - 1; i >= 0; i-- {
+			if n.Content[i].Line <= line {
+				return nodeFields(n.Content[i], line, append(fields, fmt.Sprintf("[%d]", i)))
+			}
+		}
+	}
+	return fields
+}
+
+// explainPanel documents the field under the tree cursor or, outside tree
+// mode, the field on the top line of the viewport.
+func (m *model) explainPanel(width, height int) string {
+	m.entity.Data.mu.RLock()
+	selectedResource := m.entity.Data.selectedResource
+	query := m.entity.Data.specQuery
+	var fields []string
+	if m.entity.Data.treeMode {
+		if node := m.entity.Data.treeCursorNode(); node != nil {
+			fields = node.fields
+		}
+	} else {
+		vp := m.entity.Data.viewport
+		fields = specLineFields(m.entity.Data.specLines, m.entity.Data.specNode, vp.Width, vp.YOffset)
+	}
+	var result SchemaMsg
+	loaded := false
+	if selectedResource != nil {
+		result, loaded = m.entity.Data.schemas[selectedResource.GroupVersionKind().GroupVersion()]
+	}
+	m.entity.Data.mu.RUnlock()
+
+	var body string
+	switch {
+	case selectedResource == nil:
+	case query != "":
+		body = "Field documentation is not available for query results."
+	case !loaded:
+		body = "Loading schema..."
+	case result.err != nil:
+		body = "Error: " + result.err.Error()
+	default:
+		gvk := selectedResource.GroupVersionKind()
+		doc, err := result.doc.Explain(gvk, fields)
+		if err != nil {
+			body = "Error: " + err.Error()
+			break
+		}
+
+		field := fieldPath(fields)
+		if field == "" {
+			field = gvk.Kind
+		}
+		required := "optional"
+		if doc.Required {
+			required = explainRequiredStyle.Render("required")
+		}
+		body = fmt.Sprintf("KIND:  %s\nFIELD: %s <%s>, %s\n\nDESCRIPTION:\n%s",
+			gvk.Kind, field, doc.Type, required, doc.Description)
+	}
+
+	return explainPanelStyle.
+		Width(width - 1).
+		Height(height).
+		MaxHeight(height).
+		Render(wordwrap.String(body, width-3))
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// secretWarning is appended to the notice when a saved file holds Secret
+// values.
+const secretWarning = " (WARNING: contains Secret values, written 0600)"
+
+// exportDocument renders an object with the neat or status mode applied.
+// Secret data is kept as is, so the file can be re-applied; callers write
+// Secrets with exportFileMode.
+func exportDocument(obj *unstructured.Unstructured, mode specMode, format specFormat) ([]byte, error) {
+	value := specView(obj.Object, mode)
+	if format == formatJSON {
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	}
+	return yaml.Marshal(value)
+}
+
+func exportExtension(format specFormat) string {
+	if format == formatJSON {
+		return ".json"
+	}
+	return ".yaml"
+}
+
+// exportFileMode keeps files holding Secret values private.
+func exportFileMode(secrets bool) os.FileMode {
+	if secrets {
+		return 0600
+	}
+	return 0644
+}
+
+func exportTimestamp() string {
+	return time.Now().Format("2006-01-02_15-04-05")
+}
+
+// exportSpec saves the object on the spec screen in the current mode and
+// format.
+func (m *model) exportSpec() tea.Cmd {
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		obj := m.entity.Data.selectedResource
+		mode := m.entity.Data.specMode
+		format := m.entity.Data.specFormat
+		m.entity.Data.mu.RUnlock()
+
+		if obj == nil {
+			return LogSavedMsg("Error: No resource selected")
+		}
+
+		data, err := exportDocument(obj, mode, format)
+		if err != nil {
+			return LogSavedMsg("Error: " + err.Error())
+		}
+
+		fileName := fmt.Sprintf("./%s-%s-%s%s",
+			exportTimestamp(),
+			strings.ToLower(obj.GetKind()),
+			obj.GetName(),
+			exportExtension(format))
+
+		secret := obj.GetKind() == "Secret"
+		if err := os.WriteFile(fileName, data, exportFileMode(secret)); err != nil {
+			return LogSavedMsg("Error: " + err.Error())
+		}
+		if secret {
+			return LogSavedMsg("Saved: " + fileName + secretWarning)
+		}
+		return LogSavedMsg("Saved: " + fileName)
+	}
+}
+
+// visibleResources returns the objects in the resource list, narrowed by the
+// namespace and any filter, in list order.
+func (m *model) visibleResources() []*unstructured.Unstructured {
+	m.entity.Data.mu.RLock()
+	defer m.entity.Data.mu.RUnlock()
+
+	byLabel := make(map[string]*unstructured.Unstructured, len(m.entity.Data.unstructured))
+	for _, obj := range m.entity.Data.unstructured {
+		byLabel[m.resourceLabel(obj)] = obj
+	}
+
+	var objects []*unstructured.Unstructured
+	for _, listItem := range m.entity.Data.list.VisibleItems() {
+		if obj, ok := byLabel[string(listItem.(item))]; ok {
+			objects = append(objects, obj)
+		}
+	}
+	return objects
+}
+
+// exportList saves every object in the resource list to one multi-document
+// YAML file, or to a namespace/kind/name.yaml tree when asTree is set.
+func (m *model) exportList(asTree bool) tea.Cmd {
+	objects := m.visibleResources()
+
+	m.entity.Data.mu.RLock()
+	mode := m.entity.Data.specMode
+	selectedGvr := m.entity.Data.selectedGvr
+	m.entity.Data.mu.RUnlock()
+
+	return func() tea.Msg {
+		if selectedGvr == nil || len(objects) == 0 {
+			return LogSavedMsg("Error: Nothing to save")
+		}
+
+		secrets := false
+		for _, obj := range objects {
+			secrets = secrets || obj.GetKind() == "Secret"
+		}
+		warning := ""
+		if secrets {
+			warning = secretWarning
+		}
+
+		base := fmt.Sprintf("./%s-%s", exportTimestamp(), selectedGvr.Name)
+		if asTree {
+			for _, obj := range objects {
+				data, err := exportDocument(obj, mode, formatYAML)
+				if err != nil {
+					return LogSavedMsg("Error: " + err.Error())
+				}
+
+				// Cluster-scoped objects have no namespace directory
+				path := filepath.Join(base, obj.GetNamespace(), strings.ToLower(obj.GetKind()), obj.GetName()+".yaml")
+				if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+					return LogSavedMsg("Error: " + err.Error())
+				}
+				if err := os.WriteFile(path, data, exportFileMode(obj.GetKind() == "Secret")); err != nil {
+					return LogSavedMsg("Error: " + err.Error())
+				}
+			}
+			return LogSavedMsg(fmt.Sprintf("Saved %d objects under: %s/%s", len(objects), base, warning))
+		}
+
+		var documents [][]byte
+		for _, obj := range objects {
+			data, err := exportDocument(obj, mode, formatYAML)
+			if err != nil {
+				return LogSavedMsg("Error: " + err.Error())
+			}
+			documents = append(documents, data)
+		}
+
+		fileName := base + ".yaml"
+		data := bytes.Join(documents, []byte("---\n"))
+		if err := os.WriteFile(fileName, data, exportFileMode(secrets)); err != nil {
+			return LogSavedMsg("Error: " + err.Error())
+		}
+		return LogSavedMsg(fmt.Sprintf("Saved %d objects: %s%s", len(objects), fileName, warning))
+	}
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// maxObservedVersions bounds the history kept per object for the session.
+	maxObservedVersions = 10
+	// maxObservedObjects bounds the objects with a history; the one observed
+	// least recently is dropped first.
+	maxObservedObjects = 500
+)
+
+// objectHistory is what was observed of one object of a GVR.
+type objectHistory struct {
+	gvr      schema.GroupVersionResource
+	versions []observedVersion
+}
+
+// observedVersion is one version of an object as the informer delivered it.
+type observedVersion struct {
+	seen            time.Time
+	resourceVersion string
+	obj             *unstructured.Unstructured
+}
+
+func (v observedVersion) label(marked bool) string {
+	prefix := "  "
+	if marked {
+		prefix = "● "
+	}
+	return fmt.Sprintf("%s%s  rv %s", prefix, v.seen.Format("2006-01-02 15:04:05"), v.resourceVersion)
+}
+
+// recordObservation appends obj, an object of gvr, to its history unless that
+// version is already the latest one recorded. Informer objects are never
+// modified, so they are kept without copying.
+func (a *appData) recordObservation(gvr schema.GroupVersionResource, obj *unstructured.Unstructured) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.history == nil {
+		a.history = map[types.UID]*objectHistory{}
+	}
+
+	h, known := a.history[obj.GetUID()]
+	if !known {
+		if len(a.history) >= maxObservedObjects {
+			a.dropOldestObservation()
+		}
+		h = &objectHistory{gvr: gvr}
+		a.history[obj.GetUID()] = h
+	}
+	if n := len(h.versions); n > 0 && h.versions[n-1].resourceVersion == obj.GetResourceVersion() {
+		return
+	}
+
+	h.versions = append(h.versions, observedVersion{
+		seen:            time.Now(),
+		resourceVersion: obj.GetResourceVersion(),
+		obj:             obj,
+	})
+	if len(h.versions) > maxObservedVersions {
+		h.versions = slices.Clone(h.versions[len(h.versions)-maxObservedVersions:])
+	}
+}
+
+// dropOldestObservation forgets the object observed least recently. The
+// caller must hold the lock.
+func (a *appData) dropOldestObservation() {
+	var oldest types.UID
+	var oldestSeen time.Time
+	for uid, h := range a.history {
+		seen := h.versions[len(h.versions)-1].seen
+		if oldest == "" || seen.Before(oldestSeen) {
+			oldest, oldestSeen = uid, seen
+		}
+	}
+	delete(a.history, oldest)
+}
+
+// forgetObservation drops the history of a deleted object, unless it is the
+// one on screen.
+func (a *appData) forgetObservation(obj *unstructured.Unstructured) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.selectedResource != nil && a.selectedResource.GetUID() == obj.GetUID() {
+		return
+	}
+	delete(a.history, obj.GetUID())
+}
+
+// retainObservations drops the history of objects of gvr that are not
+// listed, such as those deleted while another GVR was being watched or
+// between two polls.
+func (a *appData) retainObservations(gvr schema.GroupVersionResource, objects []*unstructured.Unstructured) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	live := make(map[types.UID]bool, len(objects))
+	for _, obj := range objects {
+		live[obj.GetUID()] = true
+	}
+	if a.selectedResource != nil {
+		live[a.selectedResource.GetUID()] = true
+	}
+	maps.DeleteFunc(a.history, func(uid types.UID, h *objectHistory) bool {
+		return h.gvr == gvr && !live[uid]
+	})
+}
+
+// observedVersions lists the selected object's history, newest first.
+func (m *model) observedVersions() []observedVersion {
+	m.entity.Data.mu.RLock()
+	defer m.entity.Data.mu.RUnlock()
+
+	if m.entity.Data.selectedResource == nil {
+		return nil
+	}
+
+	h, ok := m.entity.Data.history[m.entity.Data.selectedResource.GetUID()]
+	if !ok {
+		return nil
+	}
+	versions := slices.Clone(h.versions)
+	slices.Reverse(versions)
+	return versions
+}
+
+func (m *model) selectedObservedVersion() (observedVersion, int, bool) {
+	selected, ok := m.entity.Data.list.SelectedItem().(item)
+	if !ok {
+		return observedVersion{}, 0, false
+	}
+
+	versions := m.observedVersions()
+	for i, v := range versions {
+		if string(selected) == v.label(v.resourceVersion == m.entity.Data.historyMark) {
+			return v, i, true
+		}
+	}
+	return observedVersion{}, 0, false
+}
+
+// toggleHistoryMark marks the selected version as the base of the next diff.
+func (m *model) toggleHistoryMark() {
+	v, _, ok := m.selectedObservedVersion()
+	if !ok {
+		return
+	}
+
+	m.entity.Data.mu.Lock()
+	if m.entity.Data.historyMark == v.resourceVersion {
+		m.entity.Data.historyMark = ""
+	} else {
+		m.entity.Data.historyMark = v.resourceVersion
+	}
+	m.entity.Data.mu.Unlock()
+
+	index := m.entity.Data.list.Index()
+	m.syncList()
+	m.entity.Data.list.Select(index)
+}
+
+func observedYAML(obj *unstructured.Unstructured) (string, error) {
+	clean := maskSecretData(obj).DeepCopy()
+	unstructured.RemoveNestedField(clean.Object, "metadata", "managedFields")
+
+	data, err := yaml.Marshal(clean.Object)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// historyDiff compares the selected version with the marked one or, if
+// nothing is marked, with the version observed just before it.
+func (m *model) historyDiff() (string, string) {
+	selected, index, ok := m.selectedObservedVersion()
+	if !ok {
+		return "History", "No version selected"
+	}
+
+	versions := m.observedVersions()
+	var base *observedVersion
+	for i := range versions {
+		if versions[i].resourceVersion == m.entity.Data.historyMark && i != index {
+			base = &versions[i]
+		}
+	}
+	if base == nil && index+1 < len(versions) {
+		base = &versions[index+1]
+	}
+
+	toYaml, err := observedYAML(selected.obj)
+	if err != nil {
+		return "History", "Error marshaling object: " + err.Error()
+	}
+	if base == nil {
+		title := "First observed version, rv " + selected.resourceVersion
+		return title, highlightYAML(toYaml)
+	}
+
+	older, newer := *base, selected
+	if older.seen.After(newer.seen) {
+		older, newer = newer, older
+	}
+
+	fromYaml, err := observedYAML(older.obj)
+	if err != nil {
+		return "History", "Error marshaling object: " + err.Error()
+	}
+	toYaml, err = observedYAML(newer.obj)
+	if err != nil {
+		return "History", "Error marshaling object: " + err.Error()
+	}
+
+	title := fmt.Sprintf("Diff rv %s → rv %s", older.resourceVersion, newer.resourceVersion)
+	diff := unifiedDiff(fromYaml, toYaml, "rv "+older.resourceVersion, "rv "+newer.resourceVersion)
+	if diff == "" {
+		return title, "The two versions are identical."
+	}
+	return title, diff
+}
@@ -10,16 +10,15 @@ import (
 	"k8s.io/client-go/tools/cache"
 )
 
-
 func (m *model) runInformer() tea.Cmd {
 	return func() tea.Msg {
-		// Moving Wait() inside the return function ensures it runs 
+		// Moving Wait() inside the return function ensures it runs
 		// in a background goroutine, not the main UI thread.
 		m.entity.Data.mu.Lock()
 		if m.entity.Data.cancelInformer != nil {
 			m.entity.Data.cancelInformer()
 			m.entity.Data.mu.Unlock()
-			m.entity.Data.informerWg.Wait() 
+			m.entity.Data.informerWg.Wait()
 		} else {
 			m.entity.Data.mu.Unlock()
 		}
@@ -34,6 +33,7 @@ func (m *model) runInformer() tea.Cmd {
 		if selectedGvr == nil {
 			return nil
 		}
+		gvr := selectedGvr.GVR
 
 		if !selectedGvr.Watchable {
 			m.entity.Data.informerWg.Go(func() {
@@ -43,16 +43,20 @@ func (m *model) runInformer() tea.Cmd {
 		}
 
 		dynamicFactory := m.entity.Data.dynFact
-		informer := dynamicFactory.ForResource(selectedGvr.GVR).Informer()
+		informer := dynamicFactory.ForResource(gvr).Informer()
 
-		syncToTUI := func() {
-			objs := informer.GetStore().List()
+		storedObjects := func() []*unstructured.Unstructured {
 			var objects []*unstructured.Unstructured
-			for _, obj := range objs {
+			for _, obj := range informer.GetStore().List() {
 				if unstr, ok := obj.(*unstructured.Unstructured); ok {
 					objects = append(objects, unstr)
 				}
 			}
+			return objects
+		}
+
+		syncToTUI := func() {
+			objects := storedObjects()
 
 			select {
 			case m.entity.Data.resourceUpdates <- objects:
@@ -63,9 +67,27 @@ func (m *model) runInformer() tea.Cmd {
 		}
 
 		_, err := informer.AddEventHandler(cache.ResourceEventHandlerFuncs{
-			AddFunc:    func(obj any) { syncToTUI() },
-			UpdateFunc: func(old, new any) { syncToTUI() },
-			DeleteFunc: func(obj any) { syncToTUI() },
+			AddFunc: func(obj any) {
+				if unstr, ok := obj.(*unstructured.Unstructured); ok {
+					m.entity.Data.recordObservation(gvr, unstr)
+				}
+				syncToTUI()
+			},
+			UpdateFunc: func(old, new any) {
+				if unstr, ok := new.(*unstructured.Unstructured); ok {
+					m.entity.Data.recordObservation(gvr, unstr)
+				}
+				syncToTUI()
+			},
+			DeleteFunc: func(obj any) {
+				if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+					obj = tombstone.Obj
+				}
+				if unstr, ok := obj.(*unstructured.Unstructured); ok {
+					m.entity.Data.forgetObservation(unstr)
+				}
+				syncToTUI()
+			},
 		})
 
 		if err != nil {
@@ -75,6 +97,13 @@ func (m *model) runInformer() tea.Cmd {
 		m.entity.Data.informerWg.Go(func() {
 			informer.Run(ctx.Done())
 		})
+		// History kept from an earlier visit to this GVR may hold objects
+		// deleted since
+		m.entity.Data.informerWg.Go(func() {
+			if cache.WaitForCacheSync(ctx.Done(), informer.HasSynced) {
+				m.entity.Data.retainObservations(gvr, storedObjects())
+			}
+		})
 		return nil
 	}
 }
@@ -100,7 +129,9 @@ func (m *model) pullResourcesOnce() {
 	var objects []*unstructured.Unstructured
 	for i := range list.Items {
 		objects = append(objects, &list.Items[i])
+		m.entity.Data.recordObservation(gvr, &list.Items[i])
 	}
+	m.entity.Data.retainObservations(gvr, objects)
 
 	select {
 	case m.entity.Data.resourceUpdates <- objects:
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pmezard/go-difflib/difflib"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const changeHighlightTime = 2 * time.Second
+
+// changedLineStyle is set from the theme by applyTheme
+var changedLineStyle = "\x1b[48;5;22m" // Dark green background
+
+// ClearSpecChangesMsg ends the highlight of one live update; seq tells stale
+// ticks apart from the latest one.
+type ClearSpecChangesMsg struct {
+	seq int
+}
+
+// changedLines returns the indexes of lines in to that are new or differ
+// from from, comparing the visible text only.
+func changedLines(from, to string) map[int]bool {
+	fromLines := strings.Split(sgrPattern.ReplaceAllString(from, ""), "\n")
+	toLines := strings.Split(sgrPattern.ReplaceAllString(to, ""), "\n")
+
+	changed := map[int]bool{}
+	for _, op := range difflib.NewMatcher(fromLines, toLines).GetOpCodes() {
+		if op.Tag != 'r' && op.Tag != 'i' {
+			continue
+		}
+		for j := op.J1; j < op.J2; j++ {
+			changed[j] = true
+		}
+	}
+	return changed
+}
+
+// markLines gives the selected lines a background, re-applying it after
+// every reset in the existing colouring.
+func markLines(content string, marked map[int]bool) string {
+	if len(marked) == 0 {
+		return content
+	}
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if !marked[i] {
+			continue
+		}
+		lines[i] = changedLineStyle +
+			strings.ReplaceAll(line, resetStyle, resetStyle+changedLineStyle) +
+			resetStyle
+	}
+	return strings.Join(lines, "\n")
+}
+
+// specContent is the spec as rendered, with any live changes marked. The
+// caller must hold the lock.
+func (a *appData) specContent() string {
+	return markLines(a.selectedSpec, a.specChanges)
+}
+
+// syncLiveSpec re-renders the spec screen when the informer reports a newer
+// version of the selected object, and flags it once the object is gone.
+func (m *model) syncLiveSpec(objects []*unstructured.Unstructured) tea.Cmd {
+	m.entity.Data.mu.RLock()
+	current := m.entity.Data.selectedResource
+	m.entity.Data.mu.RUnlock()
+
+	if current == nil {
+		return nil
+	}
+
+	var latest *unstructured.Unstructured
+	for _, obj := range objects {
+		if obj.GetName() == current.GetName() && obj.GetNamespace() == current.GetNamespace() {
+			latest = obj
+			break
+		}
+	}
+
+	m.entity.Data.mu.Lock()
+	m.entity.Data.specDeleted = latest == nil
+	m.entity.Data.mu.Unlock()
+
+	if latest == nil || latest.GetResourceVersion() == current.GetResourceVersion() {
+		return nil
+	}
+
+	m.entity.Data.mu.RLock()
+	previous := m.entity.Data.selectedSpec
+	yOffset := m.entity.Data.viewport.YOffset
+	m.entity.Data.mu.RUnlock()
+
+	m.syncSpec()
+
+	m.entity.Data.mu.Lock()
+	m.entity.Data.specChanges = changedLines(previous, m.entity.Data.selectedSpec)
+	m.entity.Data.specChangeSeq++
+	seq := m.entity.Data.specChangeSeq
+	m.entity.Data.setViewportContent(m.entity.Data.specContent())
+	m.entity.Data.viewport.SetYOffset(yOffset)
+	m.entity.Data.mu.Unlock()
+
+	return tea.Tick(changeHighlightTime, func(time.Time) tea.Msg {
+		return ClearSpecChangesMsg{seq: seq}
+	})
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"github.com/alexei-ozerov/kube-traverse/internal/fsm"
 	tea "github.com/charmbracelet/bubbletea"
@@ -19,6 +20,10 @@ const (
 	spec
 	container
 	logs
+	output
+	revision
+	dataKey
+	history
 )
 
 // Events will track different actions which can impact the state.
@@ -31,11 +36,59 @@ type model struct {
 	entity *fsm.Entity[appData]
 }
 
+// newModel wires the screens of the state machine around d, starting on the
+// GVR list.
+func newModel(d *appData) *model {
+	e := &fsm.Entity[appData]{
+		Data: d,
+	}
+	m := &model{entity: e}
+
+	e.SetInitialState(gvr)
+	e.SetMachine([][]fsm.StateFn{
+		{m.gvrTransitionScreenForward, m.gvrTransitionScreenBackward},
+		{m.namespaceTransitionScreenForward, m.namespaceTransitionScreenBackward},
+		{m.resourceTransitionScreenForward, m.resourceTransitionScreenBackward},
+		{m.actionTransitionScreenForward, m.actionTransitionScreenBackward},
+		{m.specTransitionScreenForward, m.specTransitionScreenBackward},
+		{m.containerTransitionScreenForward, m.containerTransitionScreenBackward},
+		{m.logsTransitionScreenForward, m.logsTransitionScreenBackward},
+		{m.outputTransitionScreenForward, m.outputTransitionScreenBackward},
+		{m.revisionTransitionScreenForward, m.revisionTransitionScreenBackward},
+		{m.dataKeyTransitionScreenForward, m.dataKeyTransitionScreenBackward},
+		{m.historyTransitionScreenForward, m.historyTransitionScreenBackward},
+	})
+	return m
+}
+
 /*
 Runtime
 */
 
 func main() {
+	detachSpec := flag.String("detach-keys", defaultDetachKeys, "key sequence that detaches from an attached container")
+	noColor := flag.Bool("no-color", false, "disable colours, as does a non-empty NO_COLOR environment variable")
+	highContrast := flag.Bool("high-contrast", false, "use the high-contrast theme and mark selections, log levels and notifications with text")
+	flag.Parse()
+
+	keys, err := parseDetachKeys(*detachSpec)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid --detach-keys: %v\n", err)
+		os.Exit(2)
+	}
+
+	preset := ""
+	if *highContrast {
+		preset = "high-contrast"
+	}
+	t, err := loadTheme(preset)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Invalid theme %s: %v\n", themePath(), err)
+		os.Exit(2)
+	}
+	applyTheme(t)
+	setAccessibility(*noColor || noColorRequested(), t.Preset == "high-contrast")
+
 	logFile, err := setupLogging()
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to setup logging: %v\n", err)
@@ -45,31 +98,16 @@ func main() {
 
 	// Data
 	d := newAppData()
+	d.detachKeys = keys
 	err = d.fetchKubeData()
 	if err != nil {
 		log.Fatal(err)
 	}
 	d.dynFact = dynamicinformer.NewDynamicSharedInformerFactory(d.clients.Dynamic.Client, 0)
 
-	// Initialize FSM
-	e := &fsm.Entity[appData]{
-		Data: d,
-	}
-
-	// Initialize Model
-	m := &model{entity: e}
-
-	// Setup initial state
-	e.SetInitialState(gvr)
-	e.SetMachine([][]fsm.StateFn{
-		{m.gvrTransitionScreenForward, m.gvrTransitionScreenBackward},
-		{m.namespaceTransitionScreenForward, m.namespaceTransitionScreenBackward},
-		{m.resourceTransitionScreenForward, m.resourceTransitionScreenBackward},
-		{m.actionTransitionScreenForward, m.actionTransitionScreenBackward},
-		{m.specTransitionScreenForward, m.specTransitionScreenBackward},
-		{m.containerTransitionScreenForward, m.containerTransitionScreenBackward},
-		{m.logsTransitionScreenForward, m.logsTransitionScreenBackward},
-	})
+	// Initialize FSM and Model
+	m := newModel(d)
+	e := m.entity
 
 	// Okay, this is probably pedantic...
 	e.Data.program = tea.NewProgram(m, tea.WithAltScreen())
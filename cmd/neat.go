@@ -0,0 +1,236 @@
+package main
+
+import (
+	"reflect"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// specMode selects how much of the object the spec screen shows.
+type specMode int
+
+const (
+	specFull specMode = iota
+	specNeat
+	specStatus
+)
+
+func (s specMode) String() string {
+	switch s {
+	case specNeat:
+		return "neat"
+	case specStatus:
+		return "status"
+	default:
+		return "full"
+	}
+}
+
+func (s specMode) next() specMode {
+	return (s + 1) % 3
+}
+
+const lastAppliedAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+
+// serverMetadata is populated by the API server and never part of what a
+// user wrote.
+var serverMetadata = []string{"managedFields", "resourceVersion", "uid", "creationTimestamp", "generation", "selfLink"}
+
+// podSpecDefaults and containerDefaults hold values the API server fills in
+// when they are left out, so dropping them loses nothing.
+var podSpecDefaults = map[string]any{
+	"dnsPolicy":                     "ClusterFirst",
+	"restartPolicy":                 "Always",
+	"schedulerName":                 "default-scheduler",
+	"terminationGracePeriodSeconds": int64(30),
+	"enableServiceLinks":            true,
+	"preemptionPolicy":              "PreemptLowerPriority",
+	"priority":                      int64(0),
+	"securityContext":               map[string]any{},
+}
+
+var containerDefaults = map[string]any{
+	"terminationMessagePath":   "/dev/termination-log",
+	"terminationMessagePolicy": "File",
+	"resources":                map[string]any{},
+}
+
+var workloadDefaults = map[string]any{
+	"progressDeadlineSeconds": int64(600),
+	"revisionHistoryLimit":    int64(10),
+	"podManagementPolicy":     "OrderedReady",
+}
+
+var serviceDefaults = map[string]any{
+	"sessionAffinity":       "None",
+	"internalTrafficPolicy": "Cluster",
+	"ipFamilyPolicy":        "SingleStack",
+}
+
+// specView returns the part of obj the given mode shows. obj is never
+// modified.
+func specView(obj map[string]any, mode specMode) map[string]any {
+	switch mode {
+	case specNeat:
+		return neatObject(obj)
+	case specStatus:
+		metadata, _ := obj["metadata"].(map[string]any)
+		view := map[string]any{
+			"apiVersion": obj["apiVersion"],
+			"kind":       obj["kind"],
+			"metadata": map[string]any{
+				"name":      metadata["name"],
+				"namespace": metadata["namespace"],
+			},
+			"status": obj["status"],
+		}
+		if pruned, ok := pruneEmpty(runtime.DeepCopyJSON(view)).(map[string]any); ok {
+			return pruned
+		}
+		return map[string]any{}
+	default:
+		return obj
+	}
+}
+
+// neatObject strips server-populated fields and defaulted values, in the
+// spirit of kubectl-neat.
+func neatObject(obj map[string]any) map[string]any {
+	neat := runtime.DeepCopyJSON(obj)
+	delete(neat, "status")
+
+	if metadata, ok := neat["metadata"].(map[string]any); ok {
+		for _, field := range serverMetadata {
+			delete(metadata, field)
+		}
+		if annotations, ok := metadata["annotations"].(map[string]any); ok {
+			delete(annotations, lastAppliedAnnotation)
+		}
+	}
+
+	if spec, ok := neat["spec"].(map[string]any); ok {
+		switch neat["kind"] {
+		case "Deployment", "StatefulSet", "DaemonSet":
+			dropDefaults(spec, workloadDefaults)
+		case "Service":
+			dropDefaults(spec, serviceDefaults)
+		}
+	}
+
+	neatPodSpecs(neat)
+	if pruned, ok := pruneEmpty(neat).(map[string]any); ok {
+		return pruned
+	}
+	return map[string]any{}
+}
+
+// neatPodSpecs finds pod specs wherever they are nested (pods, workload
+// templates, job templates) by looking for a "containers" list.
+func neatPodSpecs(node any) {
+	switch n := node.(type) {
+	case map[string]any:
+		if containers, ok := n["containers"].([]any); ok {
+			dropDefaults(n, podSpecDefaults)
+			// Mirrors serviceAccountName
+			if sa, ok := n["serviceAccount"].(string); ok && sa == n["serviceAccountName"] {
+				delete(n, "serviceAccount")
+			}
+			for _, list := range []any{containers, n["initContainers"]} {
+				items, _ := list.([]any)
+				for _, c := range items {
+					if container, ok := c.(map[string]any); ok {
+						dropDefaults(container, containerDefaults)
+						dropTokenMounts(container)
+					}
+				}
+			}
+			dropTokenVolumes(n)
+		}
+		for _, child := range n {
+			neatPodSpecs(child)
+		}
+	case []any:
+		for _, child := range n {
+			neatPodSpecs(child)
+		}
+	}
+}
+
+func dropDefaults(obj, defaults map[string]any) {
+	for field, value := range defaults {
+		if current, ok := obj[field]; ok && reflect.DeepEqual(current, value) {
+			delete(obj, field)
+		}
+	}
+}
+
+// The service account token volume is injected by admission on every pod.
+func isTokenVolume(name any) bool {
+	s, _ := name.(string)
+	return strings.HasPrefix(s, "kube-api-access-")
+}
+
+func dropTokenVolumes(podSpec map[string]any) {
+	volumes, ok := podSpec["volumes"].([]any)
+	if !ok {
+		return
+	}
+
+	var kept []any
+	for _, v := range volumes {
+		if volume, ok := v.(map[string]any); ok && isTokenVolume(volume["name"]) {
+			continue
+		}
+		kept = append(kept, v)
+	}
+	podSpec["volumes"] = kept
+}
+
+func dropTokenMounts(container map[string]any) {
+	mounts, ok := container["volumeMounts"].([]any)
+	if !ok {
+		return
+	}
+
+	var kept []any
+	for _, m := range mounts {
+		if mount, ok := m.(map[string]any); ok && isTokenVolume(mount["name"]) {
+			continue
+		}
+		kept = append(kept, m)
+	}
+	container["volumeMounts"] = kept
+}
+
+// pruneEmpty removes nulls and empty maps and lists left behind, such as the
+// "creationTimestamp: null" in pod templates.
+func pruneEmpty(node any) any {
+	switch n := node.(type) {
+	case map[string]any:
+		for key, child := range n {
+			if pruned := pruneEmpty(child); pruned == nil {
+				delete(n, key)
+			} else {
+				n[key] = pruned
+			}
+		}
+		if len(n) == 0 {
+			return nil
+		}
+		return n
+	case []any:
+		kept := make([]any, 0, len(n))
+		for _, child := range n {
+			if pruned := pruneEmpty(child); pruned != nil {
+				kept = append(kept, pruned)
+			}
+		}
+		if len(kept) == 0 {
+			return nil
+		}
+		return kept
+	default:
+		return node
+	}
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+func TestNeatObject(t *testing.T) {
+	podSpec := func(extra map[string]any) map[string]any {
+		spec := map[string]any{
+			"dnsPolicy":                     "ClusterFirst",
+			"restartPolicy":                 "Always",
+			"schedulerName":                 "default-scheduler",
+			"terminationGracePeriodSeconds": int64(30),
+			"securityContext":               map[string]any{},
+			"serviceAccount":                "web",
+			"serviceAccountName":            "web",
+			"containers": []any{
+				map[string]any{
+					"name":                     "app",
+					"image":                    "app:v1",
+					"terminationMessagePath":   "/dev/termination-log",
+					"terminationMessagePolicy": "File",
+					"resources":                map[string]any{},
+					"volumeMounts": []any{
+						map[string]any{"name": "kube-api-access-x7k2p", "mountPath": "/var/run/secrets"},
+					},
+				},
+			},
+			"volumes": []any{
+				map[string]any{"name": "kube-api-access-x7k2p"},
+			},
+		}
+		for k, v := range extra {
+			spec[k] = v
+		}
+		return spec
+	}
+
+	tests := []struct {
+		name string
+		obj  map[string]any
+		want map[string]any
+	}{
+		{
+			name: "deployment",
+			obj: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata": map[string]any{
+					"name":              "web",
+					"uid":               "1234",
+					"resourceVersion":   "99",
+					"generation":        int64(2),
+					"creationTimestamp": "2024-01-01T00:00:00Z",
+					"managedFields":     []any{map[string]any{"manager": "kubectl"}},
+					"annotations": map[string]any{
+						lastAppliedAnnotation: "{}",
+					},
+				},
+				"spec": map[string]any{
+					"replicas":                int64(2),
+					"progressDeadlineSeconds": int64(600),
+					"revisionHistoryLimit":    int64(10),
+					"template": map[string]any{
+						"metadata": map[string]any{"creationTimestamp": nil},
+						"spec":     podSpec(nil),
+					},
+				},
+				"status": map[string]any{"replicas": int64(2)},
+			},
+			want: map[string]any{
+				"apiVersion": "apps/v1",
+				"kind":       "Deployment",
+				"metadata":   map[string]any{"name": "web"},
+				"spec": map[string]any{
+					"replicas": int64(2),
+					"template": map[string]any{
+						"spec": map[string]any{
+							"serviceAccountName": "web",
+							"containers": []any{
+								map[string]any{"name": "app", "image": "app:v1"},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "values differing from the defaults are kept",
+			obj: map[string]any{
+				"kind":     "Pod",
+				"metadata": map[string]any{"name": "job"},
+				"spec": podSpec(map[string]any{
+					"restartPolicy":  "Never",
+					"serviceAccount": "other",
+				}),
+			},
+			want: map[string]any{
+				"kind":     "Pod",
+				"metadata": map[string]any{"name": "job"},
+				"spec": map[string]any{
+					"restartPolicy":      "Never",
+					"serviceAccount":     "other",
+					"serviceAccountName": "web",
+					"containers": []any{
+						map[string]any{"name": "app", "image": "app:v1"},
+					},
+				},
+			},
+		},
+		{
+			name: "service",
+			obj: map[string]any{
+				"kind":     "Service",
+				"metadata": map[string]any{"name": "web", "uid": "1"},
+				"spec": map[string]any{
+					"sessionAffinity": "None",
+					"ipFamilyPolicy":  "PreferDualStack",
+					"ports":           []any{map[string]any{"port": int64(80)}},
+				},
+			},
+			want: map[string]any{
+				"kind":     "Service",
+				"metadata": map[string]any{"name": "web"},
+				"spec": map[string]any{
+					"ipFamilyPolicy": "PreferDualStack",
+					"ports":          []any{map[string]any{"port": int64(80)}},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := runtime.DeepCopyJSON(tt.obj)
+			got := neatObject(tt.obj)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("neatObject() = %#v\nwant %#v", got, tt.want)
+			}
+			if !reflect.DeepEqual(tt.obj, original) {
+				t.Error("neatObject modified its argument")
+			}
+		})
+	}
+}
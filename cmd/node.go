@@ -0,0 +1,231 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/alexei-ozerov/kube-traverse/internal/kube"
+	tea "github.com/charmbracelet/bubbletea"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	mirrorPodAnnotation = "kubernetes.io/config.mirror"
+	drainRetryInterval  = 5 * time.Second
+	drainPollInterval   = 2 * time.Second
+	drainTimeout        = 5 * time.Minute
+)
+
+func isNode(g *kube.ApiResource) bool {
+	return g != nil && g.GVR.Group == "" && g.GVR.Resource == "nodes"
+}
+
+func patchUnschedulable(ctx context.Context, clientset kubernetes.Interface, node string, unschedulable bool) error {
+	var value any
+	if unschedulable {
+		value = true
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"spec": map[string]any{"unschedulable": value},
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = clientset.CoreV1().Nodes().Patch(ctx, node, types.MergePatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
+func (m *model) setUnschedulable(unschedulable bool) tea.Cmd {
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		selectedResource := m.entity.Data.selectedResource
+		clientset := m.entity.Data.clients.Typed
+		m.entity.Data.mu.RUnlock()
+
+		if selectedResource == nil || clientset == nil {
+			return NotifyMsg("Error: Missing Node or Client")
+		}
+
+		err := patchUnschedulable(context.Background(), clientset, selectedResource.GetName(), unschedulable)
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		if unschedulable {
+			return NotifyMsg("Cordoned: " + selectedResource.GetName())
+		}
+		return NotifyMsg("Uncordoned: " + selectedResource.GetName())
+	}
+}
+
+// drainSkipReason explains why a drain leaves the pod in place, or returns ""
+// if the pod should be evicted.
+func drainSkipReason(pod corev1.Pod) string {
+	if _, ok := pod.Annotations[mirrorPodAnnotation]; ok {
+		return "mirror pod"
+	}
+
+	if ref := metav1.GetControllerOf(&pod); ref != nil && ref.Kind == "DaemonSet" {
+		return "managed by DaemonSet " + ref.Name
+	}
+
+	return ""
+}
+
+func (m *model) planDrain() tea.Cmd {
+	generation := m.outputGeneration()
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		selectedResource := m.entity.Data.selectedResource
+		clientset := m.entity.Data.clients.Typed
+		m.entity.Data.mu.RUnlock()
+
+		if selectedResource == nil || clientset == nil {
+			return OutputMsg{generation: generation, text: "Error: Missing Node or Client"}
+		}
+
+		node := selectedResource.GetName()
+		pods, err := clientset.CoreV1().Pods("").List(context.Background(), metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node).String(),
+		})
+		if err != nil {
+			return OutputMsg{generation: generation, text: "Error listing pods on " + node + ": " + err.Error()}
+		}
+
+		var b strings.Builder
+		var evict []corev1.Pod
+		fmt.Fprintf(&b, "Pods on %s:\n\n", node)
+		for _, pod := range pods.Items {
+			if reason := drainSkipReason(pod); reason != "" {
+				fmt.Fprintf(&b, "  skip   %s/%s (%s)\n", pod.Namespace, pod.Name, reason)
+				continue
+			}
+			fmt.Fprintf(&b, "  evict  %s/%s\n", pod.Namespace, pod.Name)
+			evict = append(evict, pod)
+		}
+
+		return PlanMsg{
+			generation: generation,
+			text:       b.String(),
+			prompt:     fmt.Sprintf("Drain node %s, evicting %d pods?", node, len(evict)),
+			cmd:        m.runDrain(node, evict),
+		}
+	}
+}
+
+// runDrain cordons the node and evicts the planned pods concurrently,
+// streaming per-pod progress into the output viewport.
+func (m *model) runDrain(node string, pods []corev1.Pod) tea.Cmd {
+	generation := m.outputGeneration()
+	return func() tea.Msg {
+		m.entity.Data.mu.Lock()
+		clientset := m.entity.Data.clients.Typed
+		ctx, cancel := context.WithTimeout(context.Background(), drainTimeout)
+		m.entity.Data.cancelTask = cancel
+		m.entity.Data.mu.Unlock()
+
+		send := func(format string, args ...any) {
+			m.entity.Data.program.Send(OutputChunkMsg{generation: generation, text: fmt.Sprintf(format, args...) + "\n"})
+		}
+
+		send("\nCordoning %s", node)
+		if err := patchUnschedulable(ctx, clientset, node, true); err != nil {
+			send("Error: %s", err.Error())
+			cancel()
+			return nil
+		}
+
+		go func() {
+			defer cancel()
+
+			var wg sync.WaitGroup
+			var failed atomic.Int64
+			for _, pod := range pods {
+				wg.Go(func() {
+					if err := drainPod(ctx, clientset, pod, send); err != nil {
+						failed.Add(1)
+					}
+				})
+			}
+			wg.Wait()
+
+			// Leaving the screen cancels the drain; the screen is gone, so
+			// say so in a notification
+			if errors.Is(ctx.Err(), context.Canceled) {
+				m.entity.Data.program.Send(NotifyMsg(fmt.Sprintf(
+					"Drain of %s aborted: %d of %d pods evicted, the node is still cordoned",
+					node, int64(len(pods))-failed.Load(), len(pods))))
+				return
+			}
+			send("\nDrain of %s finished: %d evicted, %d failed", node, int64(len(pods))-failed.Load(), failed.Load())
+		}()
+
+		return nil
+	}
+}
+
+// waitForPodGone waits until the pod is deleted, or replaced by a new pod of
+// the same name, as kubectl drain does.
+func waitForPodGone(ctx context.Context, clientset kubernetes.Interface, pod corev1.Pod) error {
+	return wait.PollUntilContextCancel(ctx, drainPollInterval, true, func(ctx context.Context) (bool, error) {
+		current, err := clientset.CoreV1().Pods(pod.Namespace).Get(ctx, pod.Name, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			return true, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		return current.UID != pod.UID, nil
+	})
+}
+
+// drainPod keeps retrying an eviction blocked by a PodDisruptionBudget until
+// it succeeds or the drain times out.
+func drainPod(ctx context.Context, clientset kubernetes.Interface, pod corev1.Pod, send func(string, ...any)) error {
+	name := pod.Namespace + "/" + pod.Name
+	send("evicting %s", name)
+
+	for {
+		err := evictPod(ctx, clientset, pod.Namespace, pod.Name)
+		if err == nil {
+			send("evicted  %s, waiting for it to terminate", name)
+			if err := waitForPodGone(ctx, clientset, pod); err != nil {
+				send("failed   %s: %s", name, err.Error())
+				return err
+			}
+			send("gone     %s", name)
+			return nil
+		}
+		if errors.Is(err, errPodGone) {
+			send("gone     %s (already deleted)", name)
+			return nil
+		}
+
+		if !apierrors.IsTooManyRequests(err) {
+			send("failed   %s: %s", name, evictionFailureReason(err))
+			return err
+		}
+
+		send("blocked  %s: %s (retrying in %s)", name, evictionFailureReason(err), drainRetryInterval)
+		select {
+		case <-ctx.Done():
+			send("failed   %s: %s", name, ctx.Err().Error())
+			return ctx.Err()
+		case <-time.After(drainRetryInterval):
+		}
+	}
+}
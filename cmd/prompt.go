@@ -0,0 +1,52 @@
+package main
+
+import (
+	"github.com/charmbracelet/bubbles/cursor"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// prompt collects a single line of input for an action. onSubmit receives the
+// entered value and may open a follow-up prompt.
+type prompt struct {
+	input    textinput.Model
+	onSubmit func(value string) tea.Cmd
+}
+
+// requestInput opens a prompt prefilled with defaultValue.
+func (m *model) requestInput(label, defaultValue string, onSubmit func(value string) tea.Cmd) {
+	input := textinput.New()
+	input.Prompt = label + ": "
+	input.PromptStyle = titleStyle
+	input.SetValue(defaultValue)
+	input.Cursor.SetMode(cursor.CursorStatic)
+	input.Focus()
+
+	m.entity.Data.mu.Lock()
+	m.entity.Data.prompt = &prompt{input: input, onSubmit: onSubmit}
+	m.entity.Data.mu.Unlock()
+}
+
+func (m *model) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	m.entity.Data.mu.Lock()
+	p := m.entity.Data.prompt
+	m.entity.Data.mu.Unlock()
+
+	switch msg.String() {
+	case "enter":
+		m.entity.Data.mu.Lock()
+		m.entity.Data.prompt = nil
+		m.entity.Data.mu.Unlock()
+		return p.onSubmit(p.input.Value())
+
+	case "esc", "ctrl+c":
+		m.entity.Data.mu.Lock()
+		m.entity.Data.prompt = nil
+		m.entity.Data.mu.Unlock()
+		return nil
+	}
+
+	var cmd tea.Cmd
+	p.input, cmd = p.input.Update(msg)
+	return cmd
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+)
+
+const (
+	defaultKubeletPort = "10250"
+	proxyBodyLimit     = 1 << 20
+)
+
+// defaultProxyPort picks the first port the object declares, so the common
+// case is a single keypress.
+func defaultProxyPort(obj *unstructured.Unstructured, resource string) string {
+	switch resource {
+	case "services":
+		ports, _, _ := unstructured.NestedSlice(obj.Object, "spec", "ports")
+		for _, p := range ports {
+			if pMap, ok := p.(map[string]any); ok {
+				if port, ok := pMap["port"].(int64); ok {
+					return fmt.Sprint(port)
+				}
+			}
+		}
+	case "pods":
+		containers, _, _ := unstructured.NestedSlice(obj.Object, "spec", "containers")
+		for _, c := range containers {
+			cMap, ok := c.(map[string]any)
+			if !ok {
+				continue
+			}
+			ports, _ := cMap["ports"].([]any)
+			for _, p := range ports {
+				if pMap, ok := p.(map[string]any); ok {
+					if port, ok := pMap["containerPort"].(int64); ok {
+						return fmt.Sprint(port)
+					}
+				}
+			}
+		}
+	case "nodes":
+		return defaultKubeletPort
+	}
+	return ""
+}
+
+func (m *model) promptProxyRequest() {
+	m.entity.Data.mu.RLock()
+	selectedResource := m.entity.Data.selectedResource
+	selectedGvr := m.entity.Data.selectedGvr
+	m.entity.Data.mu.RUnlock()
+
+	port := defaultProxyPort(selectedResource, selectedGvr.GVR.Resource)
+	m.requestInput("Port ([scheme:]port)", port, func(port string) tea.Cmd {
+		m.requestInput("Path", "/", func(path string) tea.Cmd {
+			return m.proxyGet(port, path)
+		})
+		return nil
+	})
+}
+
+// proxyGet issues a GET through the API server proxy subresource of the
+// selected pod, service or node, and renders the full response.
+func (m *model) proxyGet(port, path string) tea.Cmd {
+	generation := m.outputGeneration()
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		selectedResource := m.entity.Data.selectedResource
+		selectedGvr := m.entity.Data.selectedGvr
+		clientset := m.entity.Data.clients.Typed
+		restCfg := m.entity.Data.clients.Config
+		m.entity.Data.mu.RUnlock()
+
+		if selectedResource == nil || selectedGvr == nil || clientset == nil || restCfg == nil {
+			return OutputMsg{generation: generation, text: "Error: Missing Resource or Client"}
+		}
+
+		// "https:8443" proxies over TLS, matching the API server's name syntax.
+		target := selectedResource.GetName() + ":" + port
+		if scheme, p, ok := strings.Cut(port, ":"); ok {
+			target = scheme + ":" + selectedResource.GetName() + ":" + p
+		}
+
+		path, query, _ := strings.Cut(path, "?")
+		req := clientset.CoreV1().RESTClient().Get().
+			Resource(selectedGvr.GVR.Resource).
+			Name(target).
+			SubResource("proxy").
+			Suffix(path)
+		if selectedGvr.Namespaced {
+			req = req.Namespace(selectedResource.GetNamespace())
+		}
+
+		url := req.URL()
+		url.RawQuery = query
+
+		httpClient, err := rest.HTTPClientFor(restCfg)
+		if err != nil {
+			return OutputMsg{generation: generation, text: "Error: " + err.Error()}
+		}
+
+		resp, err := httpClient.Get(url.String())
+		if err != nil {
+			return OutputMsg{generation: generation, text: "Error: " + err.Error()}
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(io.LimitReader(resp.Body, proxyBodyLimit))
+		if err != nil {
+			return OutputMsg{generation: generation, text: "Error reading body: " + err.Error()}
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "GET %s\n%s %s\n\n", url.Path, resp.Proto, resp.Status)
+
+		headers := make([]string, 0, len(resp.Header))
+		for name := range resp.Header {
+			headers = append(headers, name)
+		}
+		slices.Sort(headers)
+		for _, name := range headers {
+			fmt.Fprintf(&b, "%s: %s\n", name, strings.Join(resp.Header[name], ", "))
+		}
+
+		b.WriteString("\n")
+		b.WriteString(renderProxyBody(resp.Header.Get("Content-Type"), body))
+		if len(body) == proxyBodyLimit {
+			fmt.Fprintf(&b, "\n\n[truncated at %d bytes]", proxyBodyLimit)
+		}
+
+		return OutputMsg{generation: generation, text: b.String()}
+	}
+}
+
+func renderProxyBody(contentType string, body []byte) string {
+	var pretty bytes.Buffer
+	if strings.Contains(contentType, "json") || json.Valid(body) {
+		if err := json.Indent(&pretty, body, "", "  "); err == nil {
+			return highlightCode(pretty.String(), "json")
+		}
+	}
+
+	if strings.Contains(contentType, "yaml") {
+		return highlightYAML(string(body))
+	}
+
+	return string(body)
+}
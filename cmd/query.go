@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// specFormat is the serialisation the spec screen renders with.
+type specFormat int
+
+const (
+	formatYAML specFormat = iota
+	formatJSON
+)
+
+func (f specFormat) String() string {
+	if f == formatJSON {
+		return "json"
+	}
+	return "yaml"
+}
+
+func (f specFormat) next() specFormat {
+	return (f + 1) % 2
+}
+
+// renderValue serialises any value in the given format and highlights it.
+func renderValue(value any, format specFormat) (string, error) {
+	if format == formatJSON {
+		data, err := json.MarshalIndent(value, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return highlightCode(string(data), "json"), nil
+	}
+
+	data, err := yaml.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return highlightYAML(string(data)), nil
+}
+
+var quotedKeyPattern = regexp.MustCompile(`\["[^"]*"\]`)
+
+// toJSONPath accepts either a JSONPath template ("{.spec.replicas}") or a
+// jq-style path (".spec.containers[] | .image") and returns a JSONPath
+// template. Only jq's path subset is understood: fields, indexes, "[]"
+// iteration and pipes between paths.
+func toJSONPath(expr string) string {
+	expr = strings.TrimSpace(expr)
+	if strings.HasPrefix(expr, "{") {
+		return expr
+	}
+
+	var path strings.Builder
+	for i, segment := range strings.Split(expr, "|") {
+		segment = strings.TrimSpace(segment)
+		if i > 0 {
+			segment = strings.TrimPrefix(segment, ".")
+			if segment != "" && !strings.HasPrefix(segment, "[") {
+				path.WriteString(".")
+			}
+		}
+		path.WriteString(segment)
+	}
+
+	// JSONPath has no quoted keys, so ["a.b"] becomes .a\.b
+	translated := quotedKeyPattern.ReplaceAllStringFunc(path.String(), func(key string) string {
+		return "." + strings.ReplaceAll(key[2:len(key)-2], ".", `\.`)
+	})
+	return "{" + strings.ReplaceAll(translated, "[]", "[*]") + "}"
+}
+
+// evalQuery runs a query against obj. A single match is returned as is and
+// several as a list.
+func evalQuery(obj map[string]any, expr string) (any, error) {
+	if strings.TrimSpace(expr) == "." {
+		return obj, nil
+	}
+
+	jp := jsonpath.New("query").AllowMissingKeys(true)
+	if err := jp.Parse(toJSONPath(expr)); err != nil {
+		return nil, err
+	}
+
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []any
+	for _, result := range results {
+		for _, value := range result {
+			matches = append(matches, value.Interface())
+		}
+	}
+
+	switch len(matches) {
+	case 0:
+		return nil, fmt.Errorf("no results for %s", expr)
+	case 1:
+		return matches[0], nil
+	default:
+		return matches, nil
+	}
+}
+
+func (m *model) promptSpecQuery() {
+	m.entity.Data.mu.RLock()
+	query := m.entity.Data.specQuery
+	m.entity.Data.mu.RUnlock()
+
+	m.requestInput("Query (JSONPath or jq path, empty to clear)", query, func(query string) tea.Cmd {
+		m.entity.Data.mu.Lock()
+		m.entity.Data.specQuery = strings.TrimSpace(query)
+		m.entity.Data.mu.Unlock()
+
+		m.syncSpec()
+		return nil
+	})
+}
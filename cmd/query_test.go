@@ -0,0 +1,79 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestToJSONPath(t *testing.T) {
+	tests := []struct {
+		expr string
+		want string
+	}{
+		{expr: "{.spec.replicas}", want: "{.spec.replicas}"},
+		{expr: ".spec.replicas", want: "{.spec.replicas}"},
+		{expr: "  .spec.replicas  ", want: "{.spec.replicas}"},
+		{expr: ".spec.containers[0].image", want: "{.spec.containers[0].image}"},
+		{expr: ".spec.containers[].image", want: "{.spec.containers[*].image}"},
+		{expr: ".spec.containers[] | .image", want: "{.spec.containers[*].image}"},
+		{expr: ".spec | .containers | [0]", want: "{.spec.containers[0]}"},
+		{expr: `.metadata.annotations["app.kubernetes.io/name"]`, want: `{.metadata.annotations.app\.kubernetes\.io/name}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			if got := toJSONPath(tt.expr); got != tt.want {
+				t.Errorf("toJSONPath(%q) = %q, want %q", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEvalQuery(t *testing.T) {
+	obj := map[string]any{
+		"metadata": map[string]any{
+			"name":        "web",
+			"annotations": map[string]any{"app.kubernetes.io/name": "shop"},
+		},
+		"spec": map[string]any{
+			"replicas": int64(3),
+			"containers": []any{
+				map[string]any{"name": "app", "image": "app:v1"},
+				map[string]any{"name": "proxy", "image": "envoy:v2"},
+			},
+		},
+	}
+
+	tests := []struct {
+		expr    string
+		want    any
+		wantErr bool
+	}{
+		{expr: ".", want: obj},
+		{expr: ".spec.replicas", want: int64(3)},
+		{expr: "{.metadata.name}", want: "web"},
+		{expr: ".spec.containers[1].name", want: "proxy"},
+		{expr: ".spec.containers[] | .image", want: []any{"app:v1", "envoy:v2"}},
+		{expr: `.metadata.annotations["app.kubernetes.io/name"]`, want: "shop"},
+		{expr: ".spec.missing", wantErr: true},
+		{expr: "{.spec[", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			got, err := evalQuery(obj, tt.expr)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("evalQuery(%q) = %v, want an error", tt.expr, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("evalQuery(%q): %v", tt.expr, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("evalQuery(%q) = %#v, want %#v", tt.expr, got, tt.want)
+			}
+		})
+	}
+}
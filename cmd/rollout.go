@@ -0,0 +1,261 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexei-ozerov/kube-traverse/internal/kube"
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+	revisionAnnotation    = "deployment.kubernetes.io/revision"
+)
+
+var (
+	replicaSetGVR         = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "replicasets"}
+	controllerRevisionGVR = schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "controllerrevisions"}
+	workloadResources     = []string{"deployments", "statefulsets", "daemonsets"}
+)
+
+// rolloutRevision is one historical pod template of a workload controller,
+// backed by either a ReplicaSet or a ControllerRevision.
+type rolloutRevision struct {
+	label    string
+	number   int64
+	template map[string]any
+	// patch holds the ControllerRevision data, which is applied verbatim on undo.
+	patch []byte
+}
+
+type RevisionsMsg []rolloutRevision
+
+func isWorkload(g *kube.ApiResource) bool {
+	return g != nil && g.GVR.Group == "apps" && slices.Contains(workloadResources, g.GVR.Resource)
+}
+
+func ownedBy(obj, owner *unstructured.Unstructured) bool {
+	for _, ref := range obj.GetOwnerReferences() {
+		if ref.UID == owner.GetUID() {
+			return true
+		}
+	}
+	return false
+}
+
+func workloadSelector(obj *unstructured.Unstructured) (string, error) {
+	raw, found, err := unstructured.NestedMap(obj.Object, "spec", "selector")
+	if err != nil || !found {
+		return "", fmt.Errorf("%s has no spec.selector", obj.GetName())
+	}
+
+	var selector metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &selector); err != nil {
+		return "", err
+	}
+
+	parsed, err := metav1.LabelSelectorAsSelector(&selector)
+	if err != nil {
+		return "", err
+	}
+	return parsed.String(), nil
+}
+
+func templateImages(template map[string]any) string {
+	var images []string
+	containers, _, _ := unstructured.NestedSlice(template, "spec", "containers")
+	for _, c := range containers {
+		if cMap, ok := c.(map[string]any); ok {
+			if image, ok := cMap["image"].(string); ok {
+				images = append(images, image)
+			}
+		}
+	}
+	return strings.Join(images, ", ")
+}
+
+func (m *model) rolloutRestart() tea.Cmd {
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		selectedGvr := m.entity.Data.selectedGvr
+		selectedResource := m.entity.Data.selectedResource
+		dynClient := m.entity.Data.clients.Dynamic
+		m.entity.Data.mu.RUnlock()
+
+		if selectedGvr == nil || selectedResource == nil || dynClient == nil {
+			return NotifyMsg("Error: Missing Resource or Client")
+		}
+
+		patch, err := json.Marshal(map[string]any{
+			"spec": map[string]any{
+				"template": map[string]any{
+					"metadata": map[string]any{
+						"annotations": map[string]string{
+							restartedAtAnnotation: time.Now().Format(time.RFC3339),
+						},
+					},
+				},
+			},
+		})
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		_, err = dynClient.Client.Resource(selectedGvr.GVR).
+			Namespace(selectedResource.GetNamespace()).
+			Patch(context.Background(), selectedResource.GetName(), types.MergePatchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		return NotifyMsg("Restarted: " + selectedResource.GetName())
+	}
+}
+
+func (m *model) fetchRevisions() tea.Cmd {
+	return func() tea.Msg {
+		selectedResource := m.refreshSelectedResource()
+
+		m.entity.Data.mu.RLock()
+		selectedGvr := m.entity.Data.selectedGvr
+		dynClient := m.entity.Data.clients.Dynamic
+		m.entity.Data.mu.RUnlock()
+
+		if selectedGvr == nil || selectedResource == nil || dynClient == nil {
+			return NotifyMsg("Error: Missing Resource or Client")
+		}
+
+		selector, err := workloadSelector(selectedResource)
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		historyGVR := controllerRevisionGVR
+		if selectedGvr.GVR.Resource == "deployments" {
+			historyGVR = replicaSetGVR
+		}
+
+		list, err := dynClient.Client.Resource(historyGVR).
+			Namespace(selectedResource.GetNamespace()).
+			List(context.Background(), metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		var revisions []rolloutRevision
+		for i := range list.Items {
+			obj := &list.Items[i]
+			if !ownedBy(obj, selectedResource) {
+				continue
+			}
+
+			var rev rolloutRevision
+			if historyGVR == replicaSetGVR {
+				number, err := strconv.ParseInt(obj.GetAnnotations()[revisionAnnotation], 10, 64)
+				if err != nil {
+					continue
+				}
+				rev.number = number
+				rev.template, _, _ = unstructured.NestedMap(obj.Object, "spec", "template")
+				unstructured.RemoveNestedField(rev.template, "metadata", "labels", "pod-template-hash")
+			} else {
+				rev.number, _, _ = unstructured.NestedInt64(obj.Object, "revision")
+				data, _, _ := unstructured.NestedMap(obj.Object, "data")
+				rev.template, _, _ = unstructured.NestedMap(data, "spec", "template")
+				// The directive only matters to the undo patch, which keeps it
+				delete(rev.template, "$patch")
+				rev.patch, err = json.Marshal(data)
+				if err != nil {
+					continue
+				}
+			}
+			revisions = append(revisions, rev)
+		}
+
+		slices.SortFunc(revisions, func(a, b rolloutRevision) int {
+			return cmp.Compare(b.number, a.number)
+		})
+
+		for i := range revisions {
+			revisions[i].label = fmt.Sprintf("revision %d  %s", revisions[i].number, templateImages(revisions[i].template))
+			if i == 0 {
+				revisions[i].label += " (current)"
+			}
+		}
+
+		return RevisionsMsg(revisions)
+	}
+}
+
+// revisionDiff compares a historical pod template against the live one.
+func (m *model) revisionDiff(rev rolloutRevision) string {
+	selectedResource := m.refreshSelectedResource()
+	if selectedResource == nil {
+		return "No resource selected"
+	}
+
+	current, _, _ := unstructured.NestedMap(selectedResource.Object, "spec", "template")
+	currentYaml, err := yaml.Marshal(current)
+	if err != nil {
+		return "Error marshaling template: " + err.Error()
+	}
+	revisionYaml, err := yaml.Marshal(rev.template)
+	if err != nil {
+		return "Error marshaling template: " + err.Error()
+	}
+
+	diff := unifiedDiff(string(currentYaml), string(revisionYaml), "current", fmt.Sprintf("revision %d", rev.number))
+	if diff == "" {
+		return fmt.Sprintf("Revision %d matches the current pod template.", rev.number)
+	}
+	return diff
+}
+
+func (m *model) rolloutUndo(rev rolloutRevision) tea.Cmd {
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		selectedGvr := m.entity.Data.selectedGvr
+		selectedResource := m.entity.Data.selectedResource
+		dynClient := m.entity.Data.clients.Dynamic
+		m.entity.Data.mu.RUnlock()
+
+		if selectedGvr == nil || selectedResource == nil || dynClient == nil {
+			return NotifyMsg("Error: Missing Resource or Client")
+		}
+
+		patchType := types.StrategicMergePatchType
+		patch := rev.patch
+		if selectedGvr.GVR.Resource == "deployments" {
+			var err error
+			patchType = types.JSONPatchType
+			patch, err = json.Marshal([]map[string]any{
+				{"op": "replace", "path": "/spec/template", "value": rev.template},
+			})
+			if err != nil {
+				return NotifyMsg("Error: " + err.Error())
+			}
+		}
+
+		_, err := dynClient.Client.Resource(selectedGvr.GVR).
+			Namespace(selectedResource.GetNamespace()).
+			Patch(context.Background(), selectedResource.GetName(), patchType, patch, metav1.PatchOptions{})
+		if err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+
+		return NotifyMsg(fmt.Sprintf("Rolled back %s to revision %d", selectedResource.GetName(), rev.number))
+	}
+}
@@ -0,0 +1,204 @@
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/wordwrap"
+)
+
+const (
+	matchStyle = "\x1b[7m" // Reverse video
+	resetStyle = "\x1b[0m"
+)
+
+// currentMatchStyle is set from the theme by applyTheme
+var currentMatchStyle = "\x1b[30;43m" // Black on yellow
+
+var sgrPattern = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// highlightMatches marks every match of re in content, which may already be
+// coloured with ANSI escapes. Matches are found on the visible text only, and
+// the marker is re-emitted after any escape inside a match so that existing
+// colours cannot cancel it. It returns the content and the line of each match.
+func highlightMatches(content string, re *regexp.Regexp, current int) (string, []int) {
+	var out strings.Builder
+	var matchLines []int
+	// The escapes in effect, replayed once a match ends
+	var active string
+
+	for lineNo, line := range strings.Split(content, "\n") {
+		if lineNo > 0 {
+			out.WriteByte('\n')
+		}
+
+		escapes := sgrPattern.FindAllStringIndex(line, -1)
+		escapeEnd := make(map[int]int, len(escapes))
+		var plain strings.Builder
+		var offsets []int
+		prev := 0
+		for _, esc := range append(escapes, []int{len(line), len(line)}) {
+			for i := prev; i < esc[0]; i++ {
+				plain.WriteByte(line[i])
+				offsets = append(offsets, i)
+			}
+			if esc[0] < len(line) {
+				escapeEnd[esc[0]] = esc[1]
+			}
+			prev = esc[1]
+		}
+
+		var bounds [][2]int
+		for _, match := range re.FindAllStringIndex(plain.String(), -1) {
+			if match[0] == match[1] {
+				continue
+			}
+			bounds = append(bounds, [2]int{offsets[match[0]], offsets[match[1]-1] + 1})
+			matchLines = append(matchLines, lineNo)
+		}
+
+		next, inMatch := 0, false
+		marker := matchStyle
+		for i := 0; i < len(line); {
+			if !inMatch && next < len(bounds) && i == bounds[next][0] {
+				marker = matchStyle
+				if len(matchLines)-len(bounds)+next == current {
+					marker = currentMatchStyle
+				}
+				out.WriteString(marker)
+				inMatch = true
+			}
+
+			if end, ok := escapeEnd[i]; ok {
+				esc := line[i:end]
+				out.WriteString(esc)
+				if esc == resetStyle || esc == "\x1b[m" {
+					active = ""
+				} else {
+					active += esc
+				}
+				if inMatch {
+					out.WriteString(marker)
+				}
+				i = end
+				continue
+			}
+
+			out.WriteByte(line[i])
+			i++
+
+			if inMatch && i == bounds[next][1] {
+				out.WriteString(resetStyle + active)
+				inMatch = false
+				next++
+			}
+		}
+	}
+
+	return out.String(), matchLines
+}
+
+// setViewportContent wraps content to the viewport and marks search matches.
+// The caller must hold the lock.
+func (a *appData) setViewportContent(content string) {
+	wrapped := wordwrap.String(content, a.viewport.Width)
+	if a.searchPattern == nil {
+		a.viewport.SetContent(wrapped)
+		return
+	}
+
+	marked, lines := highlightMatches(wrapped, a.searchPattern, a.searchIndex)
+	if a.searchIndex >= len(lines) && len(lines) > 0 {
+		a.searchIndex = len(lines) - 1
+		marked, lines = highlightMatches(wrapped, a.searchPattern, a.searchIndex)
+	}
+	a.searchLines = lines
+	a.viewport.SetContent(marked)
+}
+
+// refreshViewport re-renders the spec or log content, e.g. after the search
+// changed.
+func (m *model) refreshViewport() {
+	m.entity.Data.mu.Lock()
+	defer m.entity.Data.mu.Unlock()
+
+	switch m.entity.GetCurrentState() {
+	case spec:
+		m.entity.Data.setViewportContent(m.entity.Data.specContent())
+	case logs:
+		m.entity.Data.setViewportContent(colorizeLog(m.entity.Data.logBuffer))
+	}
+}
+
+func (m *model) clearSearch() {
+	m.entity.Data.mu.Lock()
+	m.entity.Data.searchPattern = nil
+	m.entity.Data.searchLines = nil
+	m.entity.Data.searchIndex = 0
+	m.entity.Data.mu.Unlock()
+}
+
+func (m *model) promptSearch() {
+	m.entity.Data.mu.RLock()
+	current := ""
+	if m.entity.Data.searchPattern != nil {
+		current = m.entity.Data.searchPattern.String()
+	}
+	m.entity.Data.mu.RUnlock()
+
+	m.requestInput("Search (regex, empty to clear)", current, func(query string) tea.Cmd {
+		if query == "" {
+			m.clearSearch()
+			m.refreshViewport()
+			return nil
+		}
+
+		re, err := regexp.Compile(query)
+		if err != nil {
+			return m.notify("Error: " + err.Error())
+		}
+
+		m.entity.Data.mu.Lock()
+		m.entity.Data.searchPattern = re
+		m.entity.Data.searchIndex = 0
+		m.entity.Data.mu.Unlock()
+
+		m.refreshViewport()
+		m.jumpToMatch(0)
+		return nil
+	})
+}
+
+// jumpToMatch moves the current match by delta, wrapping around, and scrolls
+// it into the middle of the viewport.
+func (m *model) jumpToMatch(delta int) {
+	m.entity.Data.mu.Lock()
+	count := len(m.entity.Data.searchLines)
+	if count == 0 {
+		m.entity.Data.mu.Unlock()
+		return
+	}
+	m.entity.Data.searchIndex = ((m.entity.Data.searchIndex+delta)%count + count) % count
+	m.entity.Data.mu.Unlock()
+
+	// The current match is styled differently, so it has to be redrawn
+	m.refreshViewport()
+
+	m.entity.Data.mu.Lock()
+	line := m.entity.Data.searchLines[m.entity.Data.searchIndex]
+	m.entity.Data.viewport.SetYOffset(max(0, line-m.entity.Data.viewport.Height/2))
+	m.entity.Data.mu.Unlock()
+}
+
+// searchStatus is the match counter shown next to the scroll percentage.
+func (a *appData) searchStatus() string {
+	if a.searchPattern == nil {
+		return ""
+	}
+	if len(a.searchLines) == 0 {
+		return fmt.Sprintf(" [no matches for /%s/]", a.searchPattern)
+	}
+	return fmt.Sprintf(" [%d/%d /%s/]", a.searchIndex+1, len(a.searchLines), a.searchPattern)
+}
@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bytes"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+	"unicode/utf8"
+
+	"github.com/alexei-ozerov/kube-traverse/internal/kube"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/reflow/wordwrap"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	secretMask    = "••••••••"
+	previewLength = 60
+	// Certificates closer than this to expiry are flagged.
+	certExpiryWarning = 30 * 24 * time.Hour
+)
+
+// dataEntry is one decoded key of a Secret or ConfigMap.
+type dataEntry struct {
+	key   string
+	value []byte
+	// binary marks ConfigMap binaryData keys.
+	binary bool
+}
+
+func isSecret(g *kube.ApiResource) bool {
+	return g != nil && g.GVR.Group == "" && g.GVR.Resource == "secrets"
+}
+
+func secretEntries(obj *unstructured.Unstructured) []dataEntry {
+	data, _, _ := unstructured.NestedStringMap(obj.Object, "data")
+
+	entries := make([]dataEntry, 0, len(data))
+	for key, encoded := range data {
+		value, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			value = []byte(encoded)
+		}
+		entries = append(entries, dataEntry{key: key, value: value})
+	}
+
+	slices.SortFunc(entries, func(a, b dataEntry) int { return strings.Compare(a.key, b.key) })
+	return entries
+}
+
+// maskSecretData returns a copy of a Secret with every data value replaced,
+// so the spec screen never shows credentials. The last-applied annotation
+// holds the same values, so it is masked too.
+func maskSecretData(obj *unstructured.Unstructured) *unstructured.Unstructured {
+	if obj.GetKind() != "Secret" {
+		return obj
+	}
+
+	masked := obj.DeepCopy()
+	for _, field := range []string{"data", "stringData"} {
+		data, found, _ := unstructured.NestedStringMap(masked.Object, field)
+		if !found {
+			continue
+		}
+		for key := range data {
+			data[key] = secretMask
+		}
+		_ = unstructured.SetNestedStringMap(masked.Object, data, field)
+	}
+
+	if annotations := masked.GetAnnotations(); annotations[lastAppliedAnnotation] != "" {
+		annotations[lastAppliedAnnotation] = secretMask
+		masked.SetAnnotations(annotations)
+	}
+	return masked
+}
+
+func dataItemLabel(entry dataEntry, revealed bool) string {
+	if entry.binary {
+		return fmt.Sprintf("%s: <binary, %d bytes>", entry.key, len(entry.value))
+	}
+	if !revealed {
+		return fmt.Sprintf("%s: %s (%d bytes)", entry.key, secretMask, len(entry.value))
+	}
+	return entry.key + ": " + previewValue(entry.value)
+}
+
+// dataKeyFromItem recovers the key from a list label. Data keys may not
+// contain ':', so the first one always ends the key.
+func dataKeyFromItem(label string) string {
+	key, _, _ := strings.Cut(label, ":")
+	return key
+}
+
+func previewValue(value []byte) string {
+	if !utf8.Valid(value) {
+		return fmt.Sprintf("<binary, %d bytes>", len(value))
+	}
+
+	line, _, multiline := strings.Cut(string(value), "\n")
+	if runes := []rune(line); len(runes) > previewLength {
+		line = string(runes[:previewLength])
+		multiline = true
+	}
+	if multiline {
+		line += " …"
+	}
+	return line
+}
+
+// renderSecretValue pretty-prints a decoded value according to what it
+// looks like: PEM blocks, docker registry credentials or JSON.
+func renderSecretValue(entry dataEntry, revealed bool) string {
+	if !revealed {
+		return fmt.Sprintf("%s (%d bytes)\n\nPress r to reveal.", secretMask, len(entry.value))
+	}
+
+	switch {
+	case bytes.Contains(entry.value, []byte("-----BEGIN ")):
+		return describePEM(entry.value)
+	case entry.key == ".dockerconfigjson" || entry.key == ".dockercfg":
+		return describeDockerConfig(entry.value)
+	case json.Valid(entry.value):
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, entry.value, "", "  "); err == nil {
+			return highlightCode(pretty.String(), "json")
+		}
+	case !utf8.Valid(entry.value):
+		return hex.Dump(entry.value)
+	}
+
+	return string(entry.value)
+}
+
+// describePEM summarises every block in the value, with validity dates for
+// certificates, followed by the PEM text itself.
+func describePEM(value []byte) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+
+	rest := value
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			fmt.Fprintf(w, "%s:\t%d bytes\n\n", block.Type, len(block.Bytes))
+			continue
+		}
+
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			fmt.Fprintf(w, "CERTIFICATE:\t%s\n\n", err.Error())
+			continue
+		}
+
+		fmt.Fprintf(w, "Subject:\t%s\n", cert.Subject.String())
+		fmt.Fprintf(w, "Issuer:\t%s\n", cert.Issuer.String())
+		if len(cert.DNSNames) > 0 {
+			fmt.Fprintf(w, "DNS SANs:\t%s\n", strings.Join(cert.DNSNames, ", "))
+		}
+		if len(cert.IPAddresses) > 0 {
+			ips := make([]string, 0, len(cert.IPAddresses))
+			for _, ip := range cert.IPAddresses {
+				ips = append(ips, ip.String())
+			}
+			fmt.Fprintf(w, "IP SANs:\t%s\n", strings.Join(ips, ", "))
+		}
+		fmt.Fprintf(w, "CA:\t%t\n", cert.IsCA)
+		fmt.Fprintf(w, "Not Before:\t%s\n", cert.NotBefore.Format(time.RFC3339))
+		fmt.Fprintf(w, "Not After:\t%s\n", cert.NotAfter.Format(time.RFC3339))
+		fmt.Fprintf(w, "Expiry:\t%s\n\n", certExpiry(cert.NotAfter))
+	}
+
+	w.Flush()
+	b.Write(value)
+	return b.String()
+}
+
+func certExpiry(notAfter time.Time) string {
+	remaining := time.Until(notAfter)
+	switch {
+	case remaining <= 0:
+		return errorStyle.Render("EXPIRED " + duration.HumanDuration(-remaining) + " ago")
+	case remaining < certExpiryWarning:
+		return warnStyle.Render("expires in " + duration.HumanDuration(remaining))
+	default:
+		return "expires in " + duration.HumanDuration(remaining)
+	}
+}
+
+type dockerAuth struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Auth     string `json:"auth"`
+	Email    string `json:"email"`
+}
+
+// describeDockerConfig lists the credentials per registry, decoding the
+// "auth" field, for both .dockerconfigjson and the legacy .dockercfg layout.
+func describeDockerConfig(value []byte) string {
+	var config struct {
+		Auths map[string]dockerAuth `json:"auths"`
+	}
+	if err := json.Unmarshal(value, &config); err != nil {
+		return "Error: " + err.Error() + "\n\n" + string(value)
+	}
+	if config.Auths == nil {
+		// .dockercfg has no "auths" wrapper
+		_ = json.Unmarshal(value, &config.Auths)
+	}
+
+	registries := make([]string, 0, len(config.Auths))
+	for registry := range config.Auths {
+		registries = append(registries, registry)
+	}
+	slices.Sort(registries)
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	for _, registry := range registries {
+		auth := config.Auths[registry]
+		if decoded, err := base64.StdEncoding.DecodeString(auth.Auth); err == nil && auth.Username == "" {
+			auth.Username, auth.Password, _ = strings.Cut(string(decoded), ":")
+		}
+
+		fmt.Fprintf(w, "Registry:\t%s\n", registry)
+		fmt.Fprintf(w, "Username:\t%s\n", auth.Username)
+		fmt.Fprintf(w, "Password:\t%s\n", auth.Password)
+		if auth.Email != "" {
+			fmt.Fprintf(w, "Email:\t%s\n", auth.Email)
+		}
+		fmt.Fprintln(w)
+	}
+	w.Flush()
+
+	var pretty bytes.Buffer
+	if err := json.Indent(&pretty, value, "", "  "); err == nil {
+		b.WriteString(highlightCode(pretty.String(), "json"))
+	}
+	return b.String()
+}
+
+func (m *model) loadDataEntries() {
+	selectedResource := m.refreshSelectedResource()
+
+	m.entity.Data.mu.Lock()
+	defer m.entity.Data.mu.Unlock()
+
+	m.entity.Data.dataEntries = nil
+	m.entity.Data.revealedKeys = map[string]bool{}
+	m.entity.Data.dataMasked = isSecret(m.entity.Data.selectedGvr)
+	if selectedResource == nil {
+		return
+	}
+
+	if m.entity.Data.dataMasked {
+		m.entity.Data.dataEntries = secretEntries(selectedResource)
+		return
+	}
+
+	// ConfigMap values are not sensitive, so they start out revealed
+	m.entity.Data.dataEntries = configMapEntries(selectedResource)
+	for _, entry := range m.entity.Data.dataEntries {
+		m.entity.Data.revealedKeys[entry.key] = true
+	}
+}
+
+// currentDataKey is the key highlighted in the key list, or the one open in
+// the output viewport.
+func (m *model) currentDataKey() (string, bool) {
+	if m.entity.GetCurrentState() == output {
+		return m.entity.Data.selectedDataKey, m.entity.Data.selectedDataKey != ""
+	}
+
+	selected, ok := m.entity.Data.list.SelectedItem().(item)
+	if !ok {
+		return "", false
+	}
+	return dataKeyFromItem(string(selected)), true
+}
+
+func (m *model) dataEntry(key string) (dataEntry, bool) {
+	m.entity.Data.mu.RLock()
+	defer m.entity.Data.mu.RUnlock()
+
+	for _, entry := range m.entity.Data.dataEntries {
+		if entry.key == key {
+			return entry, true
+		}
+	}
+	return dataEntry{}, false
+}
+
+func (m *model) showDataEntry(key string) {
+	entry, ok := m.dataEntry(key)
+	if !ok {
+		return
+	}
+
+	if !m.entity.Data.dataMasked {
+		m.openOutput("Key "+key, renderConfigMapValue(entry))
+		m.entity.Data.selectedDataKey = key
+		m.entity.Data.outputHelp = "c: copy"
+		return
+	}
+
+	m.openOutput("Key "+key, renderSecretValue(entry, m.entity.Data.revealedKeys[key]))
+	m.entity.Data.selectedDataKey = key
+	m.entity.Data.outputHelp = "r: reveal • c: copy"
+}
+
+// toggleReveal shows or hides the value of the current key, both in the key
+// list and in the output viewport.
+func (m *model) toggleReveal() {
+	if !m.entity.Data.dataMasked {
+		return
+	}
+
+	key, ok := m.currentDataKey()
+	if !ok {
+		return
+	}
+	entry, ok := m.dataEntry(key)
+	if !ok {
+		return
+	}
+
+	m.entity.Data.mu.Lock()
+	revealed := !m.entity.Data.revealedKeys[key]
+	m.entity.Data.revealedKeys[key] = revealed
+	m.entity.Data.mu.Unlock()
+
+	if m.entity.GetCurrentState() == output {
+		content := renderSecretValue(entry, revealed)
+
+		m.entity.Data.mu.Lock()
+		m.entity.Data.outputBuffer = content
+		m.entity.Data.viewport.SetContent(wordwrap.String(content, m.entity.Data.viewport.Width))
+		m.entity.Data.mu.Unlock()
+		return
+	}
+
+	m.entity.Data.list.SetItem(m.entity.Data.list.GlobalIndex(), item(dataItemLabel(entry, revealed)))
+}
+
+func (m *model) copyDataValue() tea.Cmd {
+	key, ok := m.currentDataKey()
+	if !ok {
+		return nil
+	}
+	entry, ok := m.dataEntry(key)
+	if !ok {
+		return nil
+	}
+
+	return func() tea.Msg {
+		if err := copyToClipboard(string(entry.value)); err != nil {
+			return NotifyMsg("Error: " + err.Error())
+		}
+		return NotifyMsg("Copied value of " + key)
+	}
+}
+
+// dataKeysActive reports whether the reveal and copy keys apply: on the key
+// list, or on a key opened from it.
+func (m *model) dataKeysActive() bool {
+	switch m.entity.GetCurrentState() {
+	case dataKey:
+		return m.entity.Data.list.FilterState() != list.Filtering
+	case output:
+		return m.entity.Data.choice == "data*"
+	}
+	return false
+}
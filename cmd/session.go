@@ -0,0 +1,265 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alexei-ozerov/kube-traverse/internal/kube"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/cancelreader"
+	"golang.org/x/term"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+const defaultDetachKeys = "ctrl-p,ctrl-q"
+
+// SessionMsg asks the TUI to hand the terminal over to an exec or attach
+// session. cleanup, if set, runs once the session ends.
+type SessionMsg struct {
+	opts    kube.StreamOptions
+	cleanup tea.Cmd
+}
+
+type SessionEndedMsg struct {
+	err     error
+	cleanup tea.Cmd
+}
+
+// detachKeys is the key sequence that ends a session without stopping the
+// remote process, in the same "ctrl-p,ctrl-q" notation docker uses.
+type detachKeys struct {
+	label    string
+	sequence []byte
+}
+
+func parseDetachKeys(spec string) (detachKeys, error) {
+	keys := detachKeys{label: spec}
+	for _, key := range strings.Split(spec, ",") {
+		key = strings.TrimSpace(strings.ToLower(key))
+		switch {
+		case len(key) == 1:
+			keys.sequence = append(keys.sequence, key[0])
+		case strings.HasPrefix(key, "ctrl-") && len(key) == len("ctrl-")+1:
+			c := key[len(key)-1]
+			switch {
+			case c >= 'a' && c <= 'z':
+				keys.sequence = append(keys.sequence, c-'a'+1)
+			case c >= '@' && c <= '_':
+				keys.sequence = append(keys.sequence, c-'@')
+			default:
+				return detachKeys{}, fmt.Errorf("invalid detach key %q", key)
+			}
+		default:
+			return detachKeys{}, fmt.Errorf("invalid detach key %q", key)
+		}
+	}
+
+	if len(keys.sequence) == 0 {
+		return detachKeys{}, fmt.Errorf("empty detach key sequence")
+	}
+	return keys, nil
+}
+
+// detachReader forwards input until the detach sequence is typed, holding back
+// partial matches so they never reach the container. It then calls onDetach
+// and reports EOF.
+type detachReader struct {
+	r        io.Reader
+	sequence []byte
+	onDetach func()
+
+	matched  int
+	pending  []byte
+	detached bool
+}
+
+func (d *detachReader) Read(p []byte) (int, error) {
+	buf := make([]byte, len(p))
+	for len(d.pending) == 0 && !d.detached {
+		n, err := d.r.Read(buf)
+		for _, b := range buf[:n] {
+			if b == d.sequence[d.matched] {
+				d.matched++
+				if d.matched == len(d.sequence) {
+					d.detached = true
+					d.onDetach()
+					break
+				}
+				continue
+			}
+
+			d.pending = append(d.pending, d.sequence[:d.matched]...)
+			d.matched = 0
+			if b == d.sequence[0] {
+				d.matched = 1
+				continue
+			}
+			d.pending = append(d.pending, b)
+		}
+
+		if err != nil && len(d.pending) == 0 {
+			return 0, err
+		}
+	}
+
+	if len(d.pending) == 0 {
+		return 0, io.EOF
+	}
+
+	n := copy(p, d.pending)
+	d.pending = d.pending[n:]
+	return n, nil
+}
+
+// crlfWriter restores carriage returns on plain output while the local
+// terminal is in raw mode.
+type crlfWriter struct {
+	w io.Writer
+}
+
+func (c crlfWriter) Write(p []byte) (int, error) {
+	_, err := c.w.Write(bytes.ReplaceAll(p, []byte("\n"), []byte("\r\n")))
+	return len(p), err
+}
+
+// terminalSession streams a container while Bubble Tea has released the
+// terminal. It implements tea.ExecCommand.
+type terminalSession struct {
+	clients kube.Ctx
+	opts    kube.StreamOptions
+	detach  detachKeys
+
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func (s *terminalSession) SetStdin(r io.Reader)  { s.stdin = r }
+func (s *terminalSession) SetStdout(w io.Writer) { s.stdout = w }
+func (s *terminalSession) SetStderr(w io.Writer) { s.stderr = w }
+
+func (s *terminalSession) Run() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	mode := "read-only"
+	if s.opts.Stdin {
+		mode = "interactive"
+	}
+	fmt.Fprintf(s.stdout, "Connected to %s/%s (%s, %s). Press %s to detach.\r\n",
+		s.opts.Namespace, s.opts.Pod, s.opts.Container, mode, s.detach.label)
+
+	stdout, stderr := s.stdout, s.stderr
+	var sizes remotecommand.TerminalSizeQueue
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		// Raw mode lets the detach keys through as bytes rather than signals.
+		state, err := term.MakeRaw(fd)
+		if err != nil {
+			return err
+		}
+		defer term.Restore(fd, state)
+
+		if s.opts.TTY {
+			sizes = watchTerminalSize(ctx, int(os.Stdout.Fd()))
+		} else {
+			stdout, stderr = crlfWriter{s.stdout}, crlfWriter{s.stderr}
+		}
+	}
+
+	// Bubble Tea reads the same stdin once it resumes, so the reader must be
+	// cancelled rather than left blocked on a keystroke.
+	input, err := cancelreader.NewReader(s.stdin)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	defer input.Cancel()
+
+	keys := &detachReader{r: input, sequence: s.detach.sequence, onDetach: cancel}
+	if !s.opts.Stdin {
+		// Nothing is forwarded, but the detach keys and ctrl-c still return to kt.
+		go func() {
+			buf := make([]byte, 32)
+			for {
+				n, err := keys.Read(buf)
+				if bytes.IndexByte(buf[:n], 0x03) >= 0 {
+					cancel()
+					return
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+
+	err = s.clients.Stream(ctx, s.opts, keys, stdout, stderr, sizes)
+	if ctx.Err() != nil {
+		// Detaching cancels the stream; that is not a failure.
+		return nil
+	}
+	return err
+}
+
+// terminalSizeQueue feeds local terminal resizes to the remote TTY.
+type terminalSizeQueue chan remotecommand.TerminalSize
+
+func (q terminalSizeQueue) Next() *remotecommand.TerminalSize {
+	size, ok := <-q
+	if !ok {
+		return nil
+	}
+	return &size
+}
+
+// watchTerminalSize polls rather than waiting on SIGWINCH so that it behaves
+// the same on every platform we release for.
+func watchTerminalSize(ctx context.Context, fd int) terminalSizeQueue {
+	queue := make(terminalSizeQueue, 1)
+
+	go func() {
+		defer close(queue)
+
+		var last remotecommand.TerminalSize
+		ticker := time.NewTicker(250 * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			width, height, err := term.GetSize(fd)
+			if err == nil && (uint16(width) != last.Width || uint16(height) != last.Height) {
+				last = remotecommand.TerminalSize{Width: uint16(width), Height: uint16(height)}
+				select {
+				case queue <- last:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return queue
+}
+
+func (m *model) runSession(msg SessionMsg) tea.Cmd {
+	m.entity.Data.mu.RLock()
+	clients := m.entity.Data.clients
+	detach := m.entity.Data.detachKeys
+	m.entity.Data.mu.RUnlock()
+
+	session := &terminalSession{clients: clients, opts: msg.opts, detach: detach}
+	return tea.Exec(session, func(err error) tea.Msg {
+		return SessionEndedMsg{err: err, cleanup: msg.cleanup}
+	})
+}
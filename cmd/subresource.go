@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"gopkg.in/yaml.v3"
+)
+
+// fetchSubresource GETs the chosen subresource of the selected object and
+// renders it as highlighted YAML. Used for every action without a dedicated UI.
+func (m *model) fetchSubresource(subresource string) tea.Cmd {
+	generation := m.outputGeneration()
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		selectedGvr := m.entity.Data.selectedGvr
+		selectedResource := m.entity.Data.selectedResource
+		dynClient := m.entity.Data.clients.Dynamic
+		m.entity.Data.mu.RUnlock()
+
+		if selectedGvr == nil || selectedResource == nil || dynClient == nil {
+			return OutputMsg{generation: generation, text: "Error: Missing Resource or Client"}
+		}
+
+		obj, err := dynClient.GetSubresource(
+			context.Background(),
+			selectedGvr.GVR,
+			selectedResource.GetNamespace(),
+			selectedResource.GetName(),
+			subresource,
+		)
+		if err != nil {
+			return OutputMsg{generation: generation, text: fmt.Sprintf("Error fetching %s/%s: %s", selectedGvr.Name, subresource, err.Error())}
+		}
+
+		yamlData, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return OutputMsg{generation: generation, text: "Error marshaling subresource: " + err.Error()}
+		}
+
+		return OutputMsg{generation: generation, text: highlightYAML(string(yamlData))}
+	}
+}
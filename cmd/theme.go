@@ -0,0 +1,293 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/alecthomas/chroma/v2/formatters"
+	"github.com/alecthomas/chroma/v2/styles"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"gopkg.in/yaml.v3"
+)
+
+// themeColors are lipgloss colours: ANSI numbers such as "170" or hex
+// values such as "#ff79c6".
+type themeColors struct {
+	Title        string `yaml:"title"`
+	Selection    string `yaml:"selection"`
+	Notification string `yaml:"notification"`
+	Warning      string `yaml:"warning"`
+	Error        string `yaml:"error"`
+	Muted        string `yaml:"muted"`
+	LogInfo      string `yaml:"logInfo"`
+	LogWarn      string `yaml:"logWarn"`
+	LogError     string `yaml:"logError"`
+	LogDebug     string `yaml:"logDebug"`
+	DiffAdd      string `yaml:"diffAdd"`
+	DiffDel      string `yaml:"diffDel"`
+	DiffHunk     string `yaml:"diffHunk"`
+	TreeKey      string `yaml:"treeKey"`
+	TreeCount    string `yaml:"treeCount"`
+	// Backgrounds of lines changed by a live update and of the current
+	// search match, and the match's text
+	ChangedLine     string `yaml:"changedLine"`
+	SearchMatch     string `yaml:"searchMatch"`
+	SearchMatchText string `yaml:"searchMatchText"`
+	// Text on the notification, confirmation and deleted-object banners
+	NotificationText string `yaml:"notificationText"`
+	WarningText      string `yaml:"warningText"`
+	ErrorText        string `yaml:"errorText"`
+}
+
+// theme is read from the theme file. Unset fields keep the preset's value.
+type theme struct {
+	Preset string `yaml:"preset"`
+	// Any chroma style, e.g. "dracula" or "solarized-light"
+	ChromaStyle string `yaml:"chromaStyle"`
+	// A chroma terminal formatter; empty picks one the terminal supports
+	Formatter string      `yaml:"formatter"`
+	Colors    themeColors `yaml:"colors"`
+}
+
+var themePresets = map[string]theme{
+	"dark": {
+		ChromaStyle: "monokai",
+		Colors: themeColors{
+			Title:        "170",
+			Selection:    "170",
+			Notification: "10",
+			Warning:      "11",
+			Error:        "9",
+			Muted:        "8",
+			LogInfo:      "10",
+			LogWarn:      "11",
+			LogError:     "9",
+			LogDebug:     "13",
+			DiffAdd:      "10",
+			DiffDel:      "9",
+			DiffHunk:     "14",
+			TreeKey:      "170",
+			TreeCount:    "8",
+
+			ChangedLine:     "22",
+			SearchMatch:     "3",
+			SearchMatchText: "0",
+
+			NotificationText: "0",
+			WarningText:      "0",
+			ErrorText:        "15",
+		},
+	},
+	"light": {
+		ChromaStyle: "github",
+		Colors: themeColors{
+			Title:        "90",
+			Selection:    "90",
+			Notification: "114",
+			Warning:      "221",
+			Error:        "124",
+			Muted:        "244",
+			LogInfo:      "28",
+			LogWarn:      "130",
+			LogError:     "124",
+			LogDebug:     "90",
+			DiffAdd:      "28",
+			DiffDel:      "124",
+			DiffHunk:     "25",
+			TreeKey:      "90",
+			TreeCount:    "244",
+
+			ChangedLine:     "194",
+			SearchMatch:     "220",
+			SearchMatchText: "0",
+
+			NotificationText: "0",
+			WarningText:      "0",
+			ErrorText:        "15",
+		},
+	},
+	// Bright colours only, for low vision and washed-out displays
+	"high-contrast": {
+		ChromaStyle: "bw",
+		Colors: themeColors{
+			Title:        "15",
+			Selection:    "11",
+			Notification: "15",
+			Warning:      "11",
+			Error:        "9",
+			Muted:        "7",
+			LogInfo:      "15",
+			LogWarn:      "11",
+			LogError:     "9",
+			LogDebug:     "14",
+			DiffAdd:      "10",
+			DiffDel:      "9",
+			DiffHunk:     "14",
+			TreeKey:      "15",
+			TreeCount:    "7",
+
+			ChangedLine:     "4",
+			SearchMatch:     "11",
+			SearchMatchText: "0",
+
+			NotificationText: "0",
+			WarningText:      "0",
+			ErrorText:        "0",
+		},
+	},
+}
+
+// The chroma style and formatter used by highlightCode
+var (
+	chromaStyle     = "monokai"
+	chromaFormatter = "terminal256"
+)
+
+func themePath() string {
+	return filepath.Join(os.Getenv("HOME"), ".kube", "traverse_theme.yaml")
+}
+
+// loadTheme reads the theme file, falling back to the dark preset when there
+// is none. A non-empty preset overrides the file's.
+func loadTheme(preset string) (theme, error) {
+	custom := theme{Preset: "dark"}
+	data, err := os.ReadFile(themePath())
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return theme{}, err
+	}
+	if err := yaml.Unmarshal(data, &custom); err != nil {
+		return theme{}, err
+	}
+	if preset != "" {
+		custom.Preset = preset
+	}
+
+	t, ok := themePresets[custom.Preset]
+	if !ok {
+		return theme{}, fmt.Errorf("unknown preset %q, expected dark, light or high-contrast", custom.Preset)
+	}
+	t.Preset = custom.Preset
+
+	if custom.ChromaStyle != "" {
+		if _, ok := styles.Registry[custom.ChromaStyle]; !ok {
+			return theme{}, fmt.Errorf("unknown chroma style %q", custom.ChromaStyle)
+		}
+		t.ChromaStyle = custom.ChromaStyle
+	}
+	if custom.Formatter != "" {
+		if _, ok := formatters.Registry[custom.Formatter]; !ok {
+			return theme{}, fmt.Errorf("unknown formatter %q", custom.Formatter)
+		}
+		t.Formatter = custom.Formatter
+	}
+
+	overlay := func(value *string, custom string) {
+		if custom != "" {
+			*value = custom
+		}
+	}
+	overlay(&t.Colors.Title, custom.Colors.Title)
+	overlay(&t.Colors.Selection, custom.Colors.Selection)
+	overlay(&t.Colors.Notification, custom.Colors.Notification)
+	overlay(&t.Colors.Warning, custom.Colors.Warning)
+	overlay(&t.Colors.Error, custom.Colors.Error)
+	overlay(&t.Colors.Muted, custom.Colors.Muted)
+	overlay(&t.Colors.LogInfo, custom.Colors.LogInfo)
+	overlay(&t.Colors.LogWarn, custom.Colors.LogWarn)
+	overlay(&t.Colors.LogError, custom.Colors.LogError)
+	overlay(&t.Colors.LogDebug, custom.Colors.LogDebug)
+	overlay(&t.Colors.DiffAdd, custom.Colors.DiffAdd)
+	overlay(&t.Colors.DiffDel, custom.Colors.DiffDel)
+	overlay(&t.Colors.DiffHunk, custom.Colors.DiffHunk)
+	overlay(&t.Colors.TreeKey, custom.Colors.TreeKey)
+	overlay(&t.Colors.TreeCount, custom.Colors.TreeCount)
+	overlay(&t.Colors.ChangedLine, custom.Colors.ChangedLine)
+	overlay(&t.Colors.SearchMatch, custom.Colors.SearchMatch)
+	overlay(&t.Colors.SearchMatchText, custom.Colors.SearchMatchText)
+	overlay(&t.Colors.NotificationText, custom.Colors.NotificationText)
+	overlay(&t.Colors.WarningText, custom.Colors.WarningText)
+	overlay(&t.Colors.ErrorText, custom.Colors.ErrorText)
+
+	return t, nil
+}
+
+// terminalFormatter picks the richest chroma formatter the terminal
+// supports.
+func terminalFormatter() string {
+	switch lipgloss.ColorProfile() {
+	case termenv.TrueColor:
+		return "terminal16m"
+	case termenv.ANSI256:
+		return "terminal256"
+	case termenv.ANSI:
+		return "terminal16"
+	default:
+		return "noop"
+	}
+}
+
+// sgrColors is the escape sequence for a foreground and a background colour
+// in the terminal's colour profile, or fallback when the terminal has no
+// colour.
+func sgrColors(foreground, background, fallback string) string {
+	profile := lipgloss.ColorProfile()
+	var params []string
+	if foreground != "" {
+		if seq := profile.Color(foreground).Sequence(false); seq != "" {
+			params = append(params, seq)
+		}
+	}
+	if background != "" {
+		if seq := profile.Color(background).Sequence(true); seq != "" {
+			params = append(params, seq)
+		}
+	}
+	if len(params) == 0 {
+		return fallback
+	}
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
+
+// applyTheme restyles the UI. It must run before the first list is built.
+func applyTheme(t theme) {
+	chromaStyle = t.ChromaStyle
+	chromaFormatter = t.Formatter
+	if chromaFormatter == "" {
+		chromaFormatter = terminalFormatter()
+	}
+
+	c := t.Colors
+	titleStyle = titleStyle.Foreground(lipgloss.Color(c.Title))
+	selectedItemStyle = selectedItemStyle.Foreground(lipgloss.Color(c.Selection))
+	treeKeyStyle = treeKeyStyle.Foreground(lipgloss.Color(c.TreeKey))
+	treeCountStyle = treeCountStyle.Foreground(lipgloss.Color(c.TreeCount))
+
+	notificationStyle = notificationStyle.
+		Foreground(lipgloss.Color(c.NotificationText)).
+		Background(lipgloss.Color(c.Notification))
+	confirmStyle = confirmStyle.
+		Foreground(lipgloss.Color(c.WarningText)).
+		Background(lipgloss.Color(c.Warning))
+	deletedBannerStyle = deletedBannerStyle.
+		Foreground(lipgloss.Color(c.ErrorText)).
+		Background(lipgloss.Color(c.Error))
+	explainRequiredStyle = explainRequiredStyle.Foreground(lipgloss.Color(c.Error))
+
+	infoStyle = infoStyle.Foreground(lipgloss.Color(c.LogInfo))
+	warnStyle = warnStyle.Foreground(lipgloss.Color(c.LogWarn))
+	errorStyle = errorStyle.Foreground(lipgloss.Color(c.LogError))
+	debugStyle = debugStyle.Foreground(lipgloss.Color(c.LogDebug))
+
+	diffAddStyle = diffAddStyle.Foreground(lipgloss.Color(c.DiffAdd))
+	diffDelStyle = diffDelStyle.Foreground(lipgloss.Color(c.DiffDel))
+	diffHunkStyle = diffHunkStyle.Foreground(lipgloss.Color(c.DiffHunk))
+
+	// These wrap text that is already coloured, so they are raw sequences
+	changedLineStyle = sgrColors("", c.ChangedLine, noColorChangedLineStyle)
+	currentMatchStyle = sgrColors(c.SearchMatchText, c.SearchMatch, noColorCurrentMatchStyle)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const defaultTokenExpiration = time.Hour
+
+func (m *model) promptServiceAccountToken() {
+	m.requestInput("Audience (optional)", "", func(audience string) tea.Cmd {
+		m.requestInput("Expiration", defaultTokenExpiration.String(), func(expiration string) tea.Cmd {
+			duration, err := time.ParseDuration(expiration)
+			if err != nil {
+				return m.notify("Error: " + err.Error())
+			}
+			return m.requestServiceAccountToken(audience, duration)
+		})
+		return nil
+	})
+}
+
+type TokenIssuedMsg struct {
+	generation int
+	token      string
+	view       string
+}
+
+// requestServiceAccountToken creates a TokenRequest for the selected
+// ServiceAccount and decodes the resulting JWT for display.
+func (m *model) requestServiceAccountToken(audience string, expiration time.Duration) tea.Cmd {
+	generation := m.outputGeneration()
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		sa := m.entity.Data.selectedResource
+		clientset := m.entity.Data.clients.Typed
+		m.entity.Data.mu.RUnlock()
+
+		if sa == nil || clientset == nil {
+			return OutputMsg{generation: generation, text: "Error: Missing ServiceAccount or Client"}
+		}
+
+		seconds := int64(expiration.Seconds())
+		req := &authenticationv1.TokenRequest{
+			Spec: authenticationv1.TokenRequestSpec{
+				ExpirationSeconds: &seconds,
+			},
+		}
+		if audience != "" {
+			req.Spec.Audiences = []string{audience}
+		}
+
+		resp, err := clientset.CoreV1().ServiceAccounts(sa.GetNamespace()).
+			CreateToken(context.Background(), sa.GetName(), req, metav1.CreateOptions{})
+		if err != nil {
+			return OutputMsg{generation: generation, text: "Error: " + err.Error()}
+		}
+
+		var b strings.Builder
+		fmt.Fprintf(&b, "ServiceAccount: %s/%s\n", sa.GetNamespace(), sa.GetName())
+		fmt.Fprintf(&b, "Expires:        %s\n\n", resp.Status.ExpirationTimestamp.Format(time.RFC3339))
+		b.WriteString(decodeJWT(resp.Status.Token))
+
+		return TokenIssuedMsg{generation: generation, token: resp.Status.Token, view: b.String()}
+	}
+}
+
+// decodeJWT pretty-prints the header and claims of a token without verifying
+// it, annotating the registered time claims with readable dates.
+func decodeJWT(token string) string {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return "Token is not a JWT"
+	}
+
+	var b strings.Builder
+	for i, title := range []string{"Header", "Claims"} {
+		raw, err := base64.RawURLEncoding.DecodeString(parts[i])
+		if err != nil {
+			fmt.Fprintf(&b, "%s: %s\n\n", title, err.Error())
+			continue
+		}
+
+		var pretty bytes.Buffer
+		if err := json.Indent(&pretty, raw, "", "  "); err != nil {
+			fmt.Fprintf(&b, "%s: %s\n\n", title, err.Error())
+			continue
+		}
+		fmt.Fprintf(&b, "%s:\n%s\n\n", title, highlightCode(pretty.String(), "json"))
+
+		if title != "Claims" {
+			continue
+		}
+
+		var claims map[string]any
+		if err := json.Unmarshal(raw, &claims); err != nil {
+			continue
+		}
+		for _, claim := range []string{"iat", "nbf", "exp"} {
+			if ts, ok := claims[claim].(float64); ok {
+				fmt.Fprintf(&b, "%s: %s\n", claim, time.Unix(int64(ts), 0).Format(time.RFC3339))
+			}
+		}
+	}
+
+	return b.String()
+}
+
+func (m *model) promptKubeconfigPath() {
+	m.entity.Data.mu.RLock()
+	sa := m.entity.Data.selectedResource
+	m.entity.Data.mu.RUnlock()
+
+	defaultPath := fmt.Sprintf("./%s-%s.kubeconfig", sa.GetNamespace(), sa.GetName())
+	m.requestInput("Write kubeconfig to", defaultPath, func(path string) tea.Cmd {
+		return m.writeServiceAccountKubeconfig(path)
+	})
+}
+
+// writeServiceAccountKubeconfig writes a standalone kubeconfig that talks to
+// the current cluster as the selected ServiceAccount.
+func (m *model) writeServiceAccountKubeconfig(path string) tea.Cmd {
+	return func() tea.Msg {
+		m.entity.Data.mu.RLock()
+		sa := m.entity.Data.selectedResource
+		token := m.entity.Data.serviceAccountToken
+		restCfg := m.entity.Data.clients.Config
+		m.entity.Data.mu.RUnlock()
+
+		if sa == nil || restCfg == nil || token == "" {
+			return LogSavedMsg("Error: Missing ServiceAccount Token")
+		}
+
+		caData := restCfg.CAData
+		if len(caData) == 0 && restCfg.CAFile != "" {
+			data, err := os.ReadFile(restCfg.CAFile)
+			if err != nil {
+				return LogSavedMsg("Error: " + err.Error())
+			}
+			caData = data
+		}
+
+		name := sa.GetNamespace() + "-" + sa.GetName()
+		cfg := clientcmdapi.NewConfig()
+		cfg.Clusters[name] = &clientcmdapi.Cluster{
+			Server:                   restCfg.Host,
+			CertificateAuthorityData: caData,
+			InsecureSkipTLSVerify:    restCfg.Insecure,
+			TLSServerName:            restCfg.ServerName,
+		}
+		cfg.AuthInfos[name] = &clientcmdapi.AuthInfo{Token: token}
+		cfg.Contexts[name] = &clientcmdapi.Context{
+			Cluster:   name,
+			AuthInfo:  name,
+			Namespace: sa.GetNamespace(),
+		}
+		cfg.CurrentContext = name
+
+		data, err := clientcmd.Write(*cfg)
+		if err != nil {
+			return LogSavedMsg("Error: " + err.Error())
+		}
+
+		// The file holds a bearer token, so keep it private.
+		if err := os.WriteFile(path, data, 0600); err != nil {
+			return LogSavedMsg("Error: " + err.Error())
+		}
+
+		return LogSavedMsg("Written: " + path)
+	}
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDecodeJWT(t *testing.T) {
+	segment := func(s string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(s))
+	}
+	header := segment(`{"alg":"RS256","kid":"abc"}`)
+	claims := segment(`{"sub":"system:serviceaccount:default:web","iat":1700000000,"exp":1700003600}`)
+	date := func(unix int64) string {
+		return time.Unix(unix, 0).Format(time.RFC3339)
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		want    []string
+		notWant []string
+	}{
+		{
+			name:  "service account token",
+			token: header + "." + claims + ".signature",
+			want: []string{
+				"Header:",
+				`"kid": "abc"`,
+				"Claims:",
+				`"sub": "system:serviceaccount:default:web"`,
+				"iat: " + date(1700000000),
+				"exp: " + date(1700003600),
+			},
+			notWant: []string{"nbf:"},
+		},
+		{
+			name:  "not a JWT",
+			token: "opaque-token",
+			want:  []string{"Token is not a JWT"},
+		},
+		{
+			name:  "claims are not base64",
+			token: header + ".!!!.signature",
+			want:  []string{`"alg": "RS256"`, "Claims: illegal base64 data"},
+		},
+		{
+			name:    "header is not JSON",
+			token:   segment("not json") + "." + claims + ".signature",
+			want:    []string{"Header: invalid character", "exp: " + date(1700003600)},
+			notWant: []string{"Header:\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sgrPattern.ReplaceAllString(decodeJWT(tt.token), "")
+			for _, want := range tt.want {
+				if !strings.Contains(got, want) {
+					t.Errorf("decodeJWT() is missing %q:\n%s", want, got)
+				}
+			}
+			for _, notWant := range tt.notWant {
+				if strings.Contains(got, notWant) {
+					t.Errorf("decodeJWT() should not contain %q:\n%s", notWant, got)
+				}
+			}
+		})
+	}
+}
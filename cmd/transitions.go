@@ -31,6 +31,10 @@ func (m *model) namespaceTransitionScreenBackward() (fsm.State, bool) {
 
 // Resource Transitions
 func (m *model) resourceTransitionScreenForward() (fsm.State, bool) {
+	// comparing stays set while the comparison is on screen
+	if m.entity.Data.comparing {
+		return m.enterOutput()
+	}
 	return action, true
 }
 
@@ -47,11 +51,35 @@ func (m *model) actionTransitionScreenForward() (fsm.State, bool) {
 		return spec, true
 	}
 
-	if m.entity.Data.choice == "log*" {
+	if m.entity.Data.choice == "log*" || m.entity.Data.choice == "attach*" {
 		return container, true
 	}
 
-	return action, false
+	if m.entity.Data.choice == "rollout-history*" {
+		return revision, true
+	}
+
+	if m.entity.Data.choice == "data*" {
+		return dataKey, true
+	}
+
+	if m.entity.Data.choice == "history*" {
+		return history, true
+	}
+
+	switch m.entity.Data.choice {
+	case "rollout-restart*", "cordon*", "uncordon*", "ephemeralcontainers*", "debug*", "toggle-suspend*":
+		return action, false
+	case "run-now*":
+		// Only moves on once the triggered Job's pod has been swapped in
+		if m.entity.Data.returnResource != nil {
+			return container, true
+		}
+		return action, false
+	}
+
+	// Anything without a dedicated screen is fetched as a raw subresource
+	return m.enterOutput()
 }
 func (m *model) actionTransitionScreenBackward() (fsm.State, bool) { return resource, true }
 
@@ -63,9 +91,17 @@ func (m *model) specTransitionScreenBackward() (fsm.State, bool) {
 
 // Container Transitios
 func (m *model) containerTransitionScreenForward() (fsm.State, bool) {
+	// Attaching hands over the whole terminal, so the picker stays put
+	if m.entity.Data.choice == "attach*" {
+		return container, false
+	}
 	return logs, true
 }
 func (m *model) containerTransitionScreenBackward() (fsm.State, bool) {
+	if m.entity.Data.returnResource != nil {
+		m.entity.Data.selectedResource = m.entity.Data.returnResource
+		m.entity.Data.returnResource = nil
+	}
 	return action, true
 }
 
@@ -75,5 +111,47 @@ func (m *model) logsTransitionScreenBackward() (fsm.State, bool) {
 	if m.entity.Data.cancelLog != nil {
 		m.entity.Data.cancelLog()
 	}
+	// Chunks already sent must not reach the next screen's viewport
+	m.entity.Data.mu.Lock()
+	m.entity.Data.logGeneration++
+	m.entity.Data.mu.Unlock()
 	return container, true
 }
+
+// Output Transitions
+func (m *model) outputTransitionScreenForward() (fsm.State, bool) { return output, false }
+func (m *model) outputTransitionScreenBackward() (fsm.State, bool) {
+	if m.entity.Data.cancelTask != nil {
+		m.entity.Data.cancelTask()
+	}
+	m.entity.Data.comparing = false
+	m.entity.Data.mu.Lock()
+	m.entity.Data.outputGeneration++
+	m.entity.Data.mu.Unlock()
+	return m.entity.Data.outputOrigin, true
+}
+
+// enterOutput records which screen opened the output viewport so that going
+// back returns there.
+func (m *model) enterOutput() (fsm.State, bool) {
+	m.entity.Data.outputOrigin = m.entity.GetCurrentState()
+	return output, true
+}
+
+// Revision Transitions
+func (m *model) revisionTransitionScreenForward() (fsm.State, bool) { return m.enterOutput() }
+func (m *model) revisionTransitionScreenBackward() (fsm.State, bool) {
+	return action, true
+}
+
+// Data Key Transitions
+func (m *model) dataKeyTransitionScreenForward() (fsm.State, bool) { return m.enterOutput() }
+func (m *model) dataKeyTransitionScreenBackward() (fsm.State, bool) {
+	return action, true
+}
+
+// History Transitions
+func (m *model) historyTransitionScreenForward() (fsm.State, bool) { return m.enterOutput() }
+func (m *model) historyTransitionScreenBackward() (fsm.State, bool) {
+	return action, true
+}
@@ -0,0 +1,226 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+)
+
+var (
+	treeCursorStyle = lipgloss.NewStyle().Reverse(true)
+	treeKeyStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+	treeCountStyle  = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+)
+
+// treeNode is one field or list element of an object in tree mode.
+type treeNode struct {
+	key      string
+	path     string
+	fields   []string
+	depth    int
+	value    any
+	isList   bool
+	children []*treeNode
+}
+
+func (n *treeNode) isContainer() bool {
+	return n.children != nil
+}
+
+// fieldPath joins field names and list indexes into a path such as
+// "spec.containers[0].env".
+func fieldPath(fields []string) string {
+	var b strings.Builder
+	for _, field := range fields {
+		if b.Len() > 0 && !strings.HasPrefix(field, "[") {
+			b.WriteByte('.')
+		}
+		b.WriteString(field)
+	}
+	return b.String()
+}
+
+// buildTree turns an unstructured value into nodes, with map keys sorted as
+// the YAML view sorts them.
+func buildTree(value any, fields []string) *treeNode {
+	node := &treeNode{path: fieldPath(fields), fields: fields, depth: len(fields)}
+	if len(fields) > 0 {
+		node.key = fields[len(fields)-1]
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+
+		node.children = []*treeNode{}
+		for _, k := range keys {
+			node.children = append(node.children, buildTree(v[k], append(slices.Clip(fields), k)))
+		}
+	case []any:
+		node.isList = true
+		node.children = []*treeNode{}
+		for i, item := range v {
+			node.children = append(node.children, buildTree(item, append(slices.Clip(fields), fmt.Sprintf("[%d]", i))))
+		}
+	default:
+		node.value = v
+	}
+
+	return node
+}
+
+// visibleTreeNodes flattens the tree, skipping the children of folded nodes.
+// The root itself is not shown.
+func visibleTreeNodes(root *treeNode, expanded map[string]bool) []*treeNode {
+	var nodes []*treeNode
+	var walk func(n *treeNode)
+	walk = func(n *treeNode) {
+		for _, child := range n.children {
+			nodes = append(nodes, child)
+			if child.isContainer() && expanded[child.path] {
+				walk(child)
+			}
+		}
+	}
+
+	if root == nil {
+		return nil
+	}
+	if !root.isContainer() {
+		// A query can narrow the view down to a single value
+		return []*treeNode{root}
+	}
+	walk(root)
+	return nodes
+}
+
+func treeScalar(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		line, _, multiline := strings.Cut(v, "\n")
+		if multiline {
+			line += " …"
+		}
+		return line
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func renderTreeLine(n *treeNode, expanded bool, width int) string {
+	indent := strings.Repeat("  ", max(n.depth-1, 0))
+
+	switch {
+	case !n.isContainer():
+		return runewidth.Truncate(indent+"  "+n.key+": "+treeScalar(n.value), width, "…")
+	case expanded:
+		return indent + "▾ " + treeKeyStyle.Render(n.key)
+	default:
+		count := fmt.Sprintf("{%d fields}", len(n.children))
+		if n.isList {
+			count = fmt.Sprintf("[%d items]", len(n.children))
+		}
+		return indent + "▸ " + treeKeyStyle.Render(n.key) + " " + treeCountStyle.Render(count)
+	}
+}
+
+// renderTree draws the visible nodes, one per line, with the cursor line
+// highlighted. The caller must hold the lock.
+func (a *appData) renderTree() string {
+	nodes := visibleTreeNodes(a.specTree, a.treeExpanded)
+	a.treeCursor = min(a.treeCursor, max(len(nodes)-1, 0))
+
+	lines := make([]string, 0, len(nodes))
+	for i, n := range nodes {
+		line := renderTreeLine(n, a.treeExpanded[n.path], a.viewport.Width)
+		if i == a.treeCursor {
+			line = treeCursorStyle.Render(sgrPattern.ReplaceAllString(line, ""))
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// treeCursorNode is the node under the cursor. The caller must hold the lock.
+func (a *appData) treeCursorNode() *treeNode {
+	nodes := visibleTreeNodes(a.specTree, a.treeExpanded)
+	if a.treeCursor >= len(nodes) {
+		return nil
+	}
+	return nodes[a.treeCursor]
+}
+
+// treeCursorPath is the field path of the node under the cursor. The caller
+// must hold the lock.
+func (a *appData) treeCursorPath() string {
+	if node := a.treeCursorNode(); node != nil {
+		return node.path
+	}
+	return ""
+}
+
+// initialTreeExpansion unfolds the top-level fields only, so the first view
+// of a large object fits on screen.
+func initialTreeExpansion(root *treeNode) map[string]bool {
+	expanded := map[string]bool{}
+	if root == nil {
+		return expanded
+	}
+	for _, child := range root.children {
+		if child.isContainer() {
+			expanded[child.path] = true
+		}
+	}
+	return expanded
+}
+
+// updateTree handles cursor movement and folding in tree mode. It reports
+// whether the key was consumed.
+func (m *model) updateTree(msg tea.KeyMsg) bool {
+	m.entity.Data.mu.Lock()
+	defer m.entity.Data.mu.Unlock()
+
+	nodes := visibleTreeNodes(m.entity.Data.specTree, m.entity.Data.treeExpanded)
+	if len(nodes) == 0 {
+		return false
+	}
+
+	cursor := m.entity.Data.treeCursor
+	switch msg.String() {
+	case "up", "k":
+		cursor = max(cursor-1, 0)
+	case "down", "j":
+		cursor = min(cursor+1, len(nodes)-1)
+	case " ":
+		node := nodes[cursor]
+		if !node.isContainer() {
+			return true
+		}
+		m.entity.Data.treeExpanded[node.path] = !m.entity.Data.treeExpanded[node.path]
+	default:
+		return false
+	}
+	m.entity.Data.treeCursor = cursor
+
+	m.entity.Data.selectedSpec = m.entity.Data.renderTree()
+	m.entity.Data.setViewportContent(m.entity.Data.specContent())
+
+	// Keep the cursor on screen
+	vp := &m.entity.Data.viewport
+	if cursor < vp.YOffset {
+		vp.SetYOffset(cursor)
+	} else if cursor >= vp.YOffset+vp.Height {
+		vp.SetYOffset(cursor - vp.Height + 1)
+	}
+	return true
+}
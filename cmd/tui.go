@@ -10,8 +10,8 @@ import (
 	"strings"
 	"time"
 
+	"github.com/alexei-ozerov/kube-traverse/internal/fsm"
 	"github.com/muesli/reflow/wordwrap"
-	"gopkg.in/yaml.v3"
 	"k8s.io/api/core/v1"
 
 	"github.com/charmbracelet/bubbles/list"
@@ -24,9 +24,9 @@ import (
 const listHeight = 28
 
 var (
-	titleStyle        = lipgloss.NewStyle().MarginLeft(2).Bold(true).Foreground(lipgloss.Color("170"))
+	titleStyle        = lipgloss.NewStyle().MarginLeft(2).Bold(true)
 	itemStyle         = lipgloss.NewStyle().PaddingLeft(4)
-	selectedItemStyle = lipgloss.NewStyle().PaddingLeft(2).Foreground(lipgloss.Color("170"))
+	selectedItemStyle = lipgloss.NewStyle().PaddingLeft(2)
 	paginationStyle   = list.DefaultStyles().PaginationStyle.PaddingLeft(4)
 	helpStyle         = list.DefaultStyles().HelpStyle.PaddingLeft(4).PaddingBottom(1)
 )
@@ -53,7 +53,7 @@ func (d itemDelegate) Render(w io.Writer, m list.Model, index int, listItem list
 	fn := itemStyle.Render
 	if index == m.Index() {
 		fn = func(s ...string) string {
-			return selectedItemStyle.Render("  " + strings.Join(s, " "))
+			return selectedItemStyle.Render(selectionMarker() + strings.Join(s, " "))
 		}
 	}
 
@@ -69,18 +69,57 @@ Messages
 
 type ResourceUpdateMsg []*unstructured.Unstructured
 type NamespaceUpdateMsg []string
-type LogChunkMsg string
+
+// OutputMsg replaces and OutputChunkMsg extends the text of the output
+// screen that was open when the work started; generation tells them apart
+// from later output screens.
+type OutputMsg struct {
+	generation int
+	text       string
+}
+
+type OutputChunkMsg struct {
+	generation int
+	text       string
+}
+
+// LogChunkMsg carries log lines from the stream of one generation; chunks
+// still in flight from a stream that has since been replaced are dropped.
+type LogChunkMsg struct {
+	generation int
+	text       string
+}
+
+// PlanMsg shows what an operation is about to do in the output viewport and
+// holds the operation back until the user confirms it.
+type PlanMsg struct {
+	generation int
+	text       string
+	prompt     string
+	cmd        tea.Cmd
+}
 
 type LogSavedMsg string
+type NotifyMsg string
 type ClearNotificationMsg struct{}
 
-// Add a notification style
+// The banner colours are set from the theme by applyTheme
 var notificationStyle = lipgloss.NewStyle().
-	Foreground(lipgloss.Color("0")).
-	Background(lipgloss.Color("10")). // Green background
 	Padding(0, 1).
 	Bold(true)
 
+var deletedBannerStyle = lipgloss.NewStyle().
+	Padding(0, 1).
+	Bold(true)
+
+var confirmStyle = lipgloss.NewStyle().
+	Padding(0, 1).
+	Bold(true)
+
+// implementedActions are the actions with a dedicated screen, marked with a
+// "*" in the action list.
+var implementedActions = []string{"spec", "log", "eviction", "ephemeralcontainers", "attach", "approval", "token", "proxy"}
+
 /*
 Model Methods
 */
@@ -94,7 +133,25 @@ func (m *model) Init() tea.Cmd {
 func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
-	if m.entity.GetCurrentState() == spec || m.entity.GetCurrentState() == logs {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.entity.Data.confirmCmd != nil {
+		return m, m.resolveConfirm(keyMsg)
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.entity.Data.prompt != nil {
+		return m, m.updatePrompt(keyMsg)
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.entity.Data.yankPending {
+		return m, m.resolveYank(keyMsg)
+	}
+
+	if keyMsg, ok := msg.(tea.KeyMsg); ok && m.entity.GetCurrentState() == spec && m.entity.Data.treeMode {
+		if m.updateTree(keyMsg) {
+			return m, nil
+		}
+	}
+
+	if isViewportState(m.entity.GetCurrentState()) && !m.screenKeyTaken(msg) {
 		var viewportCmd tea.Cmd
 		m.entity.Data.viewport, viewportCmd = m.entity.Data.viewport.Update(msg)
 		cmds = append(cmds, viewportCmd)
@@ -108,27 +165,56 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.entity.Data.viewport.Width = msg.Width
 			m.entity.Data.viewport.Height = msg.Height - 6
 
-			wrapped := wordwrap.String(colorizeLog(m.entity.Data.logBuffer), msg.Width)
-			m.entity.Data.viewport.SetContent(wrapped)
+			m.entity.Data.setViewportContent(colorizeLog(m.entity.Data.logBuffer))
 			m.entity.Data.mu.Unlock()
 		}
 
 		if m.entity.GetCurrentState() == spec {
+			m.entity.Data.mu.Lock()
+			m.entity.Data.viewport.Width = m.entity.Data.specViewportWidth(msg.Width)
+			m.entity.Data.viewport.Height = msg.Height - 6
+
+			m.entity.Data.setViewportContent(m.entity.Data.specContent())
+			m.entity.Data.mu.Unlock()
+		}
+
+		if m.entity.GetCurrentState() == output {
 			m.entity.Data.mu.Lock()
 			m.entity.Data.viewport.Width = msg.Width
 			m.entity.Data.viewport.Height = msg.Height - 6
 
-			m.entity.Data.viewport.SetContent(wordwrap.String(m.entity.Data.selectedSpec, msg.Width))
+			comparing := m.entity.Data.comparing
+			if !comparing {
+				m.entity.Data.viewport.SetContent(wordwrap.String(m.entity.Data.outputBuffer, msg.Width))
+			}
 			m.entity.Data.mu.Unlock()
+
+			// The columns of a comparison are laid out for the width
+			if comparing {
+				m.renderCompare()
+			}
 		}
 
 	case LogChunkMsg:
 		m.entity.Data.mu.Lock()
-		m.entity.Data.logBuffer += string(msg)
-		colorized := colorizeLog(m.entity.Data.logBuffer)
+		if msg.generation != m.entity.Data.logGeneration {
+			m.entity.Data.mu.Unlock()
+			return m, nil
+		}
+		m.entity.Data.logBuffer += msg.text
+		m.entity.Data.setViewportContent(colorizeLog(m.entity.Data.logBuffer))
+		m.entity.Data.mu.Unlock()
+		return m, nil
+
+	case OutputMsg:
+		if !m.isCurrentOutput(msg.generation) {
+			return m, nil
+		}
+
+		m.entity.Data.mu.Lock()
+		m.entity.Data.outputBuffer = msg.text
 		width := m.entity.Data.viewport.Width
-		wrapped := wordwrap.String(colorized, width)
-		m.entity.Data.viewport.SetContent(wrapped)
+		m.entity.Data.viewport.SetContent(wordwrap.String(m.entity.Data.outputBuffer, width))
 		m.entity.Data.mu.Unlock()
 		return m, nil
 
@@ -137,6 +223,11 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "q", "ctrl+c":
 			return m, tea.Quit
 
+		case "y":
+			if cmd, ok := m.startYank(); ok {
+				return m, cmd
+			}
+
 		case "g":
 			if m.entity.GetCurrentState() == logs {
 				m.entity.Data.viewport.GotoBottom()
@@ -148,6 +239,143 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				return m, m.saveLog()
 			}
+			if m.entity.GetCurrentState() == spec {
+				return m, m.exportSpec()
+			}
+			if m.entity.GetCurrentState() == resource && m.entity.Data.list.FilterState() != list.Filtering {
+				return m, m.exportList(false)
+			}
+
+		case "S":
+			if m.entity.GetCurrentState() == resource && m.entity.Data.list.FilterState() != list.Filtering {
+				return m, m.exportList(true)
+			}
+
+		case "a", "d":
+			if m.entity.GetCurrentState() == output && m.entity.Data.choice == "approval*" {
+				m.promptCSRDecision(keypress == "a")
+				return m, nil
+			}
+			if keypress == "d" && m.entity.GetCurrentState() == resource && m.entity.Data.list.FilterState() != list.Filtering {
+				return m, m.startCompare()
+			}
+
+		case "i":
+			if m.entity.GetCurrentState() == output && m.entity.Data.outputOrigin == resource {
+				m.toggleCompareNoise()
+				return m, nil
+			}
+
+		case "w":
+			if m.entity.GetCurrentState() == output && m.entity.Data.choice == "token*" && m.entity.Data.serviceAccountToken != "" {
+				m.promptKubeconfigPath()
+				return m, nil
+			}
+
+		case "p":
+			if m.entity.GetCurrentState() == output && m.entity.Data.choice == "proxy*" {
+				m.promptProxyRequest()
+				return m, nil
+			}
+			if m.entity.GetCurrentState() == logs {
+				return m, m.togglePreviousLogs()
+			}
+
+		case "v":
+			if m.entity.GetCurrentState() == spec {
+				m.entity.Data.mu.Lock()
+				m.entity.Data.specMode = m.entity.Data.specMode.next()
+				m.entity.Data.mu.Unlock()
+
+				m.syncSpec()
+				return m, nil
+			}
+			if m.entity.GetCurrentState() == resource && m.entity.Data.list.FilterState() != list.Filtering {
+				m.entity.Data.mu.Lock()
+				m.entity.Data.specMode = m.entity.Data.specMode.next()
+				m.entity.Data.mu.Unlock()
+
+				m.entity.Data.list.Title = m.resourceListTitle()
+				return m, nil
+			}
+
+		case "/":
+			if state := m.entity.GetCurrentState(); state == spec || state == logs {
+				m.promptSearch()
+				return m, nil
+			}
+
+		case "n", "N":
+			if state := m.entity.GetCurrentState(); state == spec || state == logs {
+				if keypress == "n" {
+					m.jumpToMatch(1)
+				} else {
+					m.jumpToMatch(-1)
+				}
+				return m, nil
+			}
+
+		case "f":
+			if m.entity.GetCurrentState() == spec {
+				m.entity.Data.mu.Lock()
+				m.entity.Data.specFormat = m.entity.Data.specFormat.next()
+				m.entity.Data.mu.Unlock()
+
+				m.syncSpec()
+				return m, nil
+			}
+
+		case "t":
+			if m.entity.GetCurrentState() == spec {
+				m.entity.Data.mu.Lock()
+				m.entity.Data.treeMode = !m.entity.Data.treeMode
+				m.entity.Data.treeExpanded = nil
+				m.entity.Data.treeCursor = 0
+				m.entity.Data.mu.Unlock()
+
+				m.syncSpec()
+				return m, nil
+			}
+
+		case "e":
+			if m.entity.GetCurrentState() == spec {
+				return m, m.toggleExplain()
+			}
+
+		case ":":
+			if m.entity.GetCurrentState() == spec {
+				m.promptSpecQuery()
+				return m, nil
+			}
+
+		case "r", "c":
+			if m.dataKeysActive() {
+				if keypress == "r" {
+					m.toggleReveal()
+					return m, nil
+				}
+				return m, m.copyDataValue()
+			}
+
+		case "m":
+			if m.entity.GetCurrentState() == history && m.entity.Data.list.FilterState() != list.Filtering {
+				m.toggleHistoryMark()
+				return m, nil
+			}
+			if m.entity.GetCurrentState() == resource && m.entity.Data.list.FilterState() != list.Filtering {
+				return m, m.toggleCompareMark()
+			}
+
+		case "u":
+			if m.entity.GetCurrentState() == revision && m.entity.Data.list.FilterState() != list.Filtering {
+				if rev, ok := m.selectedRevision(); ok {
+					m.requestConfirm(
+						fmt.Sprintf("Roll back %s to revision %d?", m.entity.Data.selectedResource.GetName(), rev.number),
+						m.rolloutUndo(rev),
+					)
+				}
+				return m, nil
+			}
 
 		case "l", "enter":
 			if m.entity.Data.list.FilterState() != list.Filtering {
@@ -177,6 +405,9 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.entity.GetCurrentState() == resource {
 			m.syncList()
 		}
+		if m.entity.GetCurrentState() == spec {
+			cmds = append(cmds, m.syncLiveSpec(msg))
+		}
 		cmds = append(cmds, m.listenForResourceUpdates())
 
 	case NamespaceUpdateMsg:
@@ -189,13 +420,92 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		cmds = append(cmds, m.listenForNamespaceUpdates())
 
-	case LogSavedMsg:
+	case OutputChunkMsg:
+		if !m.isCurrentOutput(msg.generation) {
+			return m, nil
+		}
+
 		m.entity.Data.mu.Lock()
-		m.entity.Data.exportNotification = string(msg)
+		m.entity.Data.outputBuffer += msg.text
+		width := m.entity.Data.viewport.Width
+		m.entity.Data.viewport.SetContent(wordwrap.String(m.entity.Data.outputBuffer, width))
+		m.entity.Data.viewport.GotoBottom()
 		m.entity.Data.mu.Unlock()
-		return m, tea.Tick(time.Second*3, func(t time.Time) tea.Msg {
-			return ClearNotificationMsg{}
-		})
+		return m, nil
+
+	case PlanMsg:
+		if !m.isCurrentOutput(msg.generation) {
+			return m, nil
+		}
+
+		m.entity.Data.mu.Lock()
+		m.entity.Data.outputBuffer = msg.text
+		m.entity.Data.viewport.SetContent(wordwrap.String(msg.text, m.entity.Data.viewport.Width))
+		m.entity.Data.mu.Unlock()
+
+		m.requestConfirm(msg.prompt, msg.cmd)
+		return m, nil
+
+	case TokenIssuedMsg:
+		if !m.isCurrentOutput(msg.generation) {
+			return m, nil
+		}
+
+		m.entity.Data.mu.Lock()
+		m.entity.Data.serviceAccountToken = msg.token
+		m.entity.Data.outputBuffer = msg.view
+		m.entity.Data.outputHelp = "w: write kubeconfig"
+		m.entity.Data.viewport.SetContent(wordwrap.String(msg.view, m.entity.Data.viewport.Width))
+		m.entity.Data.mu.Unlock()
+		return m, nil
+
+	case SchemaMsg:
+		m.entity.Data.storeSchema(msg)
+
+	case ClearSpecChangesMsg:
+		m.entity.Data.mu.Lock()
+		stale := msg.seq != m.entity.Data.specChangeSeq
+		if !stale {
+			m.entity.Data.specChanges = nil
+		}
+		m.entity.Data.mu.Unlock()
+
+		if !stale {
+			m.refreshViewport()
+		}
+		return m, nil
+
+	case JobPodMsg:
+		m.followJobPod(msg)
+		return m, m.notify("Following pod " + msg.pod.GetName() + " of job " + msg.job)
+
+	case SessionMsg:
+		return m, m.runSession(msg)
+
+	case SessionEndedMsg:
+		var sessionCmds []tea.Cmd
+		if msg.err != nil {
+			sessionCmds = append(sessionCmds, m.notify("Session ended: "+msg.err.Error()))
+		}
+		if msg.cleanup != nil {
+			sessionCmds = append(sessionCmds, msg.cleanup)
+		}
+		return m, tea.Batch(sessionCmds...)
+
+	case RevisionsMsg:
+		m.entity.Data.mu.Lock()
+		m.entity.Data.revisions = msg
+		m.entity.Data.mu.Unlock()
+
+		if m.entity.GetCurrentState() == revision {
+			m.syncList()
+		}
+
+	case LogSavedMsg:
+		return m, m.notify(string(msg))
+
+	case NotifyMsg:
+		return m, m.notify(string(msg))
 
 	case ClearNotificationMsg:
 		m.entity.Data.mu.Lock()
@@ -228,6 +538,7 @@ func (m *model) handleForward() (tea.Cmd, bool) {
 		m.entity.Data.mu.Unlock()
 
 		m.entity.Data.getGvrFromString()
+		m.entity.Data.compareMark = nil
 		cmd = m.runInformer()
 
 	case namespace:
@@ -239,18 +550,11 @@ func (m *model) handleForward() (tea.Cmd, bool) {
 		m.entity.Data.mu.Unlock()
 
 	case resource:
-		m.entity.Data.mu.RLock()
-		unstructuredItems := m.entity.Data.unstructured
-		m.entity.Data.mu.RUnlock()
-
-		for _, obj := range unstructuredItems {
-			if obj.GetName() == selStr {
-				m.entity.Data.mu.Lock()
-				m.entity.Data.selectedResource = obj
-				m.entity.Data.viewport = viewport.New(m.entity.Data.list.Width(), m.entity.Data.list.Height()-4)
-				m.entity.Data.mu.Unlock()
-				break
-			}
+		if obj, ok := m.selectedListResource(); ok {
+			m.entity.Data.mu.Lock()
+			m.entity.Data.selectedResource = obj
+			m.entity.Data.viewport = viewport.New(m.entity.Data.list.Width(), m.entity.Data.list.Height()-4)
+			m.entity.Data.mu.Unlock()
 		}
 		m.entity.Data.choice = ""
 
@@ -258,14 +562,98 @@ func (m *model) handleForward() (tea.Cmd, bool) {
 		m.entity.Data.mu.Lock()
 		m.entity.Data.choice = selStr
 		m.entity.Data.mu.Unlock()
-		if selStr == "spec*" {
+		switch selStr {
+		case "spec*":
+			m.entity.Data.mu.Lock()
+			m.entity.Data.specDeleted = false
+			m.entity.Data.treeExpanded = nil
+			m.entity.Data.treeCursor = 0
+			m.entity.Data.viewport.Width = m.entity.Data.specViewportWidth(m.entity.Data.list.Width())
+			m.entity.Data.mu.Unlock()
+
+			m.clearSearch()
 			m.syncSpec()
+			cmd = m.fetchSchema()
+		case "log*":
+		case "data*":
+			m.loadDataEntries()
+		case "history*":
+			m.entity.Data.mu.Lock()
+			m.entity.Data.historyMark = ""
+			m.entity.Data.mu.Unlock()
+		case "rollout-restart*":
+			cmd = m.rolloutRestart()
+		case "rollout-history*":
+			m.entity.Data.mu.Lock()
+			m.entity.Data.revisions = nil
+			m.entity.Data.mu.Unlock()
+
+			cmd = m.fetchRevisions()
+		case "approval*":
+			m.openOutput("Certificate Signing Request "+m.entity.Data.selectedResource.GetName(), "Loading...")
+			m.entity.Data.outputHelp = "a: approve • d: deny"
+			cmd = m.showCSR()
+		case "token*":
+			m.openOutput("Token for "+m.entity.Data.selectedResource.GetName(), "Choose an audience and expiration for the token.")
+			m.entity.Data.serviceAccountToken = ""
+			m.promptServiceAccountToken()
+		case "proxy*":
+			m.openOutput("Proxy to "+m.entity.Data.selectedResource.GetName(), "Choose a port and path to request.")
+			m.entity.Data.outputHelp = "p: new request"
+			m.promptProxyRequest()
+		case "toggle-suspend*":
+			cmd = m.toggleSuspend()
+		case "run-now*":
+			cmd = m.triggerCronJob()
+		case "ephemeralcontainers*":
+			m.promptDebugContainer()
+		case "debug*":
+			m.promptNodeDebug()
+		case "cordon*":
+			cmd = m.setUnschedulable(true)
+		case "uncordon*":
+			cmd = m.setUnschedulable(false)
+		case "drain*":
+			m.openOutput("Draining "+m.entity.Data.selectedResource.GetName(), "Loading...")
+			cmd = m.planDrain()
+		case "eviction*":
+			m.openOutput("Evicting "+m.entity.Data.selectedResource.GetName(), "Loading...")
+			cmd = m.planEviction()
+		default:
+			m.openOutput("Viewing "+selStr, "Loading...")
+			cmd = m.fetchSubresource(selStr)
+		}
+
+	case revision:
+		rev, ok := m.selectedRevision()
+		if !ok {
+			return nil, false
 		}
 
+		m.openOutput(fmt.Sprintf("Revision %d", rev.number), m.revisionDiff(rev))
+
+	case dataKey:
+		m.showDataEntry(dataKeyFromItem(selStr))
+
+	case history:
+		title, content := m.historyDiff()
+		m.openOutput(title, content)
+
 	case container:
 		m.entity.Data.mu.Lock()
-		m.entity.Data.selectedContainer = selStr
+		m.entity.Data.selectedContainer = containerFromItem(selStr)
+		m.entity.Data.mu.Unlock()
+
+		if m.entity.Data.choice == "attach*" {
+			cmd = m.attachContainer()
+			break
+		}
+
+		m.clearSearch()
+
+		m.entity.Data.mu.Lock()
 		m.entity.Data.logBuffer = ""
+		m.entity.Data.previousLogs = false
 		m.entity.Data.viewport = viewport.New(m.entity.Data.list.Width(), m.entity.Data.list.Height()-4)
 		m.entity.Data.mu.Unlock()
 
@@ -285,12 +673,23 @@ func (m *model) View() string {
 	var mainView string
 	state := m.entity.GetCurrentState()
 
-	if state == spec || state == logs {
+	if isViewportState(state) {
 		var helpText string
 
 		m.entity.Data.mu.RLock()
 		selectedResource := m.entity.Data.selectedResource
 		viewportContainer := m.entity.Data.viewport
+		outputTitle := m.entity.Data.outputTitle
+		outputHelp := m.entity.Data.outputHelp
+		mode := m.entity.Data.specMode
+		format := m.entity.Data.specFormat
+		query := m.entity.Data.specQuery
+		searchStatus := m.entity.Data.searchStatus()
+		specDeleted := m.entity.Data.specDeleted
+		previousLogs := m.entity.Data.previousLogs
+		treeMode := m.entity.Data.treeMode
+		treePath := m.entity.Data.treeCursorPath()
+		explainMode := m.entity.Data.explainMode
 		m.entity.Data.mu.RUnlock()
 
 		if selectedResource == nil {
@@ -300,16 +699,50 @@ func (m *model) View() string {
 		helpText = helpStyle.Render("↑ /↓ : Scroll • h/← : Back")
 
 		title := "Viewing Spec"
+		if state == spec {
+			tags := []string{mode.String(), format.String()}
+			if treeMode {
+				tags[1] = "tree"
+			}
+			if query != "" {
+				tags = append(tags, query)
+			}
+			title = fmt.Sprintf("Viewing Spec [%s]", strings.Join(tags, ", "))
+			if treeMode && treePath != "" {
+				title += " " + treePath
+			}
+			helpText = helpStyle.Render("↑ /↓ : Scroll • v: full/neat/status • f: yaml/json • t: tree • space: fold • e: explain • s: save • :: query • /: search • n/N: next/prev • h/← : Back")
+			if specDeleted {
+				title = deletedBannerStyle.Render("DELETED, showing last known state") + " " + title
+			}
+		}
 		if state == logs {
 			title = "Viewing Logs"
-			helpText = helpStyle.Render("↑ /↓ : Scroll • s: save logfile • h/← : Back")
+			if previousLogs {
+				title = "Viewing Previous Logs"
+			}
+			helpText = helpStyle.Render("↑ /↓ : Scroll • /: search • n/N: next/prev • p: previous/current • s: save logfile • h/← : Back")
+		}
+		if state == output {
+			title = outputTitle
+			searchStatus = ""
+			if outputHelp != "" {
+				helpText = helpStyle.Render("↑ /↓ : Scroll • " + outputHelp + " • h/← : Back")
+			}
+		}
+
+		body := viewportContainer.View()
+		if state == spec && explainMode {
+			panelWidth := m.entity.Data.list.Width() - viewportContainer.Width
+			body = lipgloss.JoinHorizontal(lipgloss.Top, body, m.explainPanel(panelWidth, viewportContainer.Height))
 		}
 
 		mainView = fmt.Sprintf(
-			"%s: %s (%3.f%%)\n\n%s\n\n%s",
+			"%s: %s (%3.f%%)%s\n\n%s\n\n%s",
 			title, selectedResource.GetName(),
 			viewportContainer.ScrollPercent()*100,
-			viewportContainer.View(),
+			searchStatus,
+			body,
 			helpText,
 		)
 	} else {
@@ -320,8 +753,22 @@ func (m *model) View() string {
 	notify := m.entity.Data.exportNotification
 	m.entity.Data.mu.RUnlock()
 
+	m.entity.Data.mu.RLock()
+	confirmText := m.entity.Data.confirmText
+	activePrompt := m.entity.Data.prompt
+	m.entity.Data.mu.RUnlock()
+
+	if activePrompt != nil {
+		return mainView + "\n" + activePrompt.input.View()
+	}
+
+	if confirmText != "" {
+		popup := confirmStyle.Render(confirmText + " (y/N)")
+		return mainView + "\n" + popup
+	}
+
 	if notify != "" {
-		popup := notificationStyle.Render(notify)
+		popup := notificationStyle.Render(notificationText(notify))
 		return mainView + "\n" + popup
 	}
 
@@ -332,6 +779,12 @@ func (m *model) View() string {
 Custom Methods
 */
 
+// isViewportState reports whether the state renders the shared viewport
+// rather than the list.
+func isViewportState(state fsm.State) bool {
+	return state == spec || state == logs || state == output
+}
+
 func (m *model) listenForResourceUpdates() tea.Cmd {
 	return func() tea.Msg {
 		select {
@@ -387,19 +840,16 @@ func (m *model) syncList() {
 
 	case resource:
 		m.entity.Data.mu.RLock()
-		selectedGvr := m.entity.Data.selectedGvr
 		unstructuredItems := m.entity.Data.unstructured
 		ns := m.entity.Data.nsChoice
 		m.entity.Data.mu.RUnlock()
 
-		if selectedGvr != nil {
-			title = fmt.Sprintf("Resources (%s)", selectedGvr.Name)
-		}
+		title = m.resourceListTitle()
 
 		var names []string
 		for _, unstr := range unstructuredItems {
 			if ns == "" || unstr.GetNamespace() == ns {
-				names = append(names, unstr.GetName())
+				names = append(names, m.resourceLabel(unstr))
 			}
 		}
 
@@ -416,65 +866,175 @@ func (m *model) syncList() {
 		if selectedGvr != nil {
 			title = fmt.Sprintf("Actions for %s", selectedGvr.Name)
 			for _, action := range selectedGvr.SubResources {
-				if action == "log" || action == "spec" {
+				if slices.Contains(implementedActions, action) {
 					items = append(items, item(action+"*"))
 					continue
 				}
 				items = append(items, item(action))
 			}
+
+			if isWorkload(selectedGvr) {
+				items = append(items, item("rollout-restart*"), item("rollout-history*"))
+			}
+			items = append(items, item("history*"))
+			if isSecret(selectedGvr) || isConfigMap(selectedGvr) {
+				items = append(items, item("data*"))
+			}
+			if isCronJob(selectedGvr) {
+				items = append(items, item("toggle-suspend*"), item("run-now*"))
+			}
+			if isNode(selectedGvr) {
+				items = append(items, item("cordon*"), item("uncordon*"), item("drain*"), item("debug*"))
+			}
 		}
-	case container:
+
+	case revision:
 		m.entity.Data.mu.RLock()
+		revisions := m.entity.Data.revisions
 		selectedResource := m.entity.Data.selectedResource
 		m.entity.Data.mu.RUnlock()
 
-		title = "Select Container"
 		if selectedResource != nil {
-			containers, _, _ := unstructured.NestedSlice(selectedResource.Object, "spec", "containers")
-			initContainers, _, _ := unstructured.NestedSlice(selectedResource.Object, "spec", "initContainers")
+			title = fmt.Sprintf("Rollout History (%s)", selectedResource.GetName())
+		}
+		for _, rev := range revisions {
+			items = append(items, item(rev.label))
+		}
+	case history:
+		m.entity.Data.mu.RLock()
+		selectedResource := m.entity.Data.selectedResource
+		mark := m.entity.Data.historyMark
+		m.entity.Data.mu.RUnlock()
+
+		if selectedResource != nil {
+			title = fmt.Sprintf("Observed Versions (%s)", selectedResource.GetName())
+		}
+		for _, v := range m.observedVersions() {
+			items = append(items, item(v.label(v.resourceVersion == mark)))
+		}
 
-			for _, c := range append(containers, initContainers...) {
-				if cMap, ok := c.(map[string]any); ok {
-					if name, ok := cMap["name"].(string); ok {
-						items = append(items, item(name))
-					}
-				}
+	case dataKey:
+		m.entity.Data.mu.RLock()
+		entries := m.entity.Data.dataEntries
+		revealedKeys := m.entity.Data.revealedKeys
+		selectedResource := m.entity.Data.selectedResource
+		m.entity.Data.mu.RUnlock()
+
+		if selectedResource != nil {
+			title = fmt.Sprintf("Keys (%s)", selectedResource.GetName())
+		}
+		for _, entry := range entries {
+			items = append(items, item(dataItemLabel(entry, revealedKeys[entry.key])))
+		}
+
+	case container:
+		// The informer's latest copy, so restart counts are current
+		selectedResource := m.refreshSelectedResource()
+
+		title = "Select Container"
+		if selectedResource != nil {
+			for _, label := range containerItems(selectedResource) {
+				items = append(items, item(label))
 			}
 		}
 	}
 
 	m.entity.Data.list.Title = title
 	m.entity.Data.list.SetItems(items)
+	m.entity.Data.list.AdditionalShortHelpKeys = m.listHelpKeys(state)
 
 	m.entity.Data.list.ResetFilter()
 	m.entity.Data.list.Select(0)
 	m.entity.Data.list.Paginator.Page = 0
 }
 
-func (m *model) syncSpec() {
+// resourceLabel names an object in the resource list, qualified by its
+// namespace when all namespaces are listed.
+func (m *model) resourceLabel(obj *unstructured.Unstructured) string {
+	if m.entity.Data.nsChoice == "" {
+		return resourceName(obj)
+	}
+	return obj.GetName()
+}
+
+func (m *model) selectedListResource() (*unstructured.Unstructured, bool) {
+	selected, ok := m.entity.Data.list.SelectedItem().(item)
+	if !ok {
+		return nil, false
+	}
+
 	m.entity.Data.mu.RLock()
-	selectedResource := m.entity.Data.selectedResource
-	unstructuredObject := m.entity.Data.unstructured
-	m.entity.Data.mu.RUnlock()
+	defer m.entity.Data.mu.RUnlock()
+	for _, obj := range m.entity.Data.unstructured {
+		if m.resourceLabel(obj) == string(selected) {
+			return obj, true
+		}
+	}
+	return nil, false
+}
 
+// refreshSelectedResource swaps the selected object for the informer's latest
+// copy of it, so screens never act on a stale snapshot.
+func (m *model) refreshSelectedResource() *unstructured.Unstructured {
+	m.entity.Data.mu.Lock()
+	defer m.entity.Data.mu.Unlock()
+
+	selectedResource := m.entity.Data.selectedResource
 	if selectedResource == nil {
-		return
+		return nil
 	}
 
-	for _, obj := range unstructuredObject {
+	for _, obj := range m.entity.Data.unstructured {
 		if obj.GetName() == selectedResource.GetName() &&
 			obj.GetNamespace() == selectedResource.GetNamespace() {
+			m.entity.Data.selectedResource = obj
+			return obj
+		}
+	}
+
+	return selectedResource
+}
 
+func (m *model) syncSpec() {
+	selectedResource := m.refreshSelectedResource()
+	if selectedResource == nil {
+		return
+	}
+
+	m.entity.Data.mu.Lock()
+	mode := m.entity.Data.specMode
+	format := m.entity.Data.specFormat
+	query := m.entity.Data.specQuery
+	m.entity.Data.specChanges = nil
+	m.entity.Data.specLines, m.entity.Data.specNode = nil, nil
+	m.entity.Data.mu.Unlock()
+
+	var value any = specView(maskSecretData(selectedResource).Object, mode)
+	if query != "" {
+		result, err := evalQuery(value.(map[string]any), query)
+		if err != nil {
 			m.entity.Data.mu.Lock()
-			m.entity.Data.selectedResource = obj
+			m.entity.Data.selectedSpec = "Query error: " + err.Error()
+			m.entity.Data.setViewportContent(m.entity.Data.selectedSpec)
 			m.entity.Data.mu.Unlock()
+			return
+		}
+		value = result
+	}
 
-			selectedResource = obj
-			break
+	if m.entity.Data.treeMode {
+		m.entity.Data.mu.Lock()
+		m.entity.Data.specTree = buildTree(value, nil)
+		if m.entity.Data.treeExpanded == nil {
+			m.entity.Data.treeExpanded = initialTreeExpansion(m.entity.Data.specTree)
 		}
+		m.entity.Data.selectedSpec = m.entity.Data.renderTree()
+		m.entity.Data.setViewportContent(m.entity.Data.selectedSpec)
+		m.entity.Data.mu.Unlock()
+		return
 	}
 
-	yamlData, err := yaml.Marshal(selectedResource.Object)
+	highlighted, err := renderValue(value, format)
 	if err != nil {
 		m.entity.Data.mu.Lock()
 		m.entity.Data.viewport.SetContent("Error marshaling spec: " + err.Error())
@@ -482,19 +1042,92 @@ func (m *model) syncSpec() {
 		return
 	}
 
-	highlighedYaml := highlightYAML(string(yamlData))
-	m.entity.Data.selectedSpec = highlighedYaml
+	m.entity.Data.mu.Lock()
+	m.entity.Data.selectedSpec = highlighted
+	m.entity.Data.parseSpec(highlighted)
+	m.entity.Data.setViewportContent(highlighted)
+	m.entity.Data.mu.Unlock()
+}
+
+// openOutput resets the viewport for the output screen with initial content.
+// Work started from an earlier output screen no longer reaches it.
+func (m *model) openOutput(title, content string) {
+	m.entity.Data.mu.Lock()
+	m.entity.Data.outputGeneration++
+	m.entity.Data.outputTitle = title
+	m.entity.Data.outputHelp = ""
+	m.entity.Data.outputBuffer = content
+	m.entity.Data.viewport = viewport.New(m.entity.Data.list.Width(), m.entity.Data.list.Height()-4)
+	m.entity.Data.viewport.SetContent(wordwrap.String(content, m.entity.Data.viewport.Width))
+	m.entity.Data.mu.Unlock()
+}
+
+// outputGeneration identifies the output screen on display, for messages
+// from work started there.
+func (m *model) outputGeneration() int {
+	m.entity.Data.mu.RLock()
+	defer m.entity.Data.mu.RUnlock()
+	return m.entity.Data.outputGeneration
+}
+
+// isCurrentOutput reports whether a message from the given output screen
+// generation is still on display.
+func (m *model) isCurrentOutput(generation int) bool {
+	return m.entity.GetCurrentState() == output && generation == m.outputGeneration()
+}
+
+func (m *model) notify(text string) tea.Cmd {
+	m.entity.Data.mu.Lock()
+	m.entity.Data.exportNotification = text
+	m.entity.Data.mu.Unlock()
+	return tea.Tick(time.Second*3, func(t time.Time) tea.Msg {
+		return ClearNotificationMsg{}
+	})
+}
 
+// requestConfirm holds cmd back until the user answers the y/N prompt.
+func (m *model) requestConfirm(text string, cmd tea.Cmd) {
 	m.entity.Data.mu.Lock()
-	m.entity.Data.viewport.SetContent(highlighedYaml)
+	m.entity.Data.confirmText = text
+	m.entity.Data.confirmCmd = cmd
 	m.entity.Data.mu.Unlock()
 }
 
+func (m *model) resolveConfirm(msg tea.KeyMsg) tea.Cmd {
+	m.entity.Data.mu.Lock()
+	cmd := m.entity.Data.confirmCmd
+	m.entity.Data.confirmText = ""
+	m.entity.Data.confirmCmd = nil
+	m.entity.Data.mu.Unlock()
+
+	if msg.String() == "y" {
+		return cmd
+	}
+	return nil
+}
+
+func (m *model) selectedRevision() (rolloutRevision, bool) {
+	selected, ok := m.entity.Data.list.SelectedItem().(item)
+	if !ok {
+		return rolloutRevision{}, false
+	}
+
+	m.entity.Data.mu.RLock()
+	defer m.entity.Data.mu.RUnlock()
+	for _, rev := range m.entity.Data.revisions {
+		if rev.label == string(selected) {
+			return rev, true
+		}
+	}
+	return rolloutRevision{}, false
+}
+
 func (m *model) saveLog() tea.Cmd {
 	return func() tea.Msg {
 		m.entity.Data.mu.RLock()
 		pod := m.entity.Data.selectedResource
 		container := m.entity.Data.selectedContainer
+		previous := m.entity.Data.previousLogs
 		clientset := m.entity.Data.clients.Typed
 		m.entity.Data.mu.RUnlock()
 
@@ -504,6 +1137,7 @@ func (m *model) saveLog() tea.Cmd {
 
 		req := clientset.CoreV1().Pods(pod.GetNamespace()).GetLogs(pod.GetName(), &v1.PodLogOptions{
 			Container: container,
+			Previous:  previous,
 		})
 
 		logStream, err := req.Stream(context.Background())
@@ -517,6 +1151,9 @@ func (m *model) saveLog() tea.Cmd {
 			return LogSavedMsg("Error: " + err.Error())
 		}
 
+		if previous {
+			container += "-previous"
+		}
 		logFileName := fmt.Sprintf("./%s-%s-%s.log",
 			time.Now().Format("2006-01-02_15-04-05"),
 			pod.GetName(),
@@ -536,10 +1173,16 @@ func (m *model) startLiveLogs() tea.Cmd {
 		m.entity.Data.mu.Lock()
 		pod := m.entity.Data.selectedResource
 		container := m.entity.Data.selectedContainer
+		previous := m.entity.Data.previousLogs
 		clientset := m.entity.Data.clients.Typed
 
+		if m.entity.Data.cancelLog != nil {
+			m.entity.Data.cancelLog()
+		}
 		ctx, cancel := context.WithCancel(context.Background())
 		m.entity.Data.cancelLog = cancel
+		m.entity.Data.logGeneration++
+		generation := m.entity.Data.logGeneration
 		m.entity.Data.mu.Unlock()
 
 		if pod == nil || clientset == nil {
@@ -550,19 +1193,21 @@ func (m *model) startLiveLogs() tea.Cmd {
 		req := clientset.CoreV1().Pods(pod.GetNamespace()).GetLogs(pod.GetName(), &v1.PodLogOptions{
 			Container: container,
 			TailLines: &tailLines,
-			Follow:    true,
+			// A terminated instance has nothing more to follow
+			Follow:   !previous,
+			Previous: previous,
 		})
 
 		stream, err := req.Stream(ctx)
 		if err != nil {
-			return nil
+			return NotifyMsg("Error: " + err.Error())
 		}
 
 		go func() {
 			defer stream.Close()
 			scanner := bufio.NewScanner(stream)
 			for scanner.Scan() {
-				m.entity.Data.program.Send(LogChunkMsg(scanner.Text() + "\n"))
+				m.entity.Data.program.Send(LogChunkMsg{generation: generation, text: scanner.Text() + "\n"})
 
 				select {
 				case <-ctx.Done():
@@ -2,8 +2,10 @@ package main
 
 import (
 	"fmt"
+	"github.com/alexei-ozerov/kube-traverse/internal/fsm"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"log"
 	"os"
@@ -46,7 +48,7 @@ func initializeGvrList(items []list.Item) list.Model {
 	l.Styles.HelpStyle = helpStyle
 	l.Styles.NoItems = lipgloss.NewStyle().PaddingLeft(4)
 	l.Styles.FilterPrompt = lipgloss.NewStyle().PaddingLeft(4)
-	l.Styles.FilterCursor = lipgloss.NewStyle().Foreground(lipgloss.Color("170"))
+	l.Styles.FilterCursor = lipgloss.NewStyle().Foreground(titleStyle.GetForeground())
 
 	return l
 }
@@ -54,6 +56,14 @@ func initializeGvrList(items []list.Item) list.Model {
 type listKeyMap struct {
 	selectItem key.Binding
 	back       key.Binding
+	undo       key.Binding
+	reveal     key.Binding
+	copyValue  key.Binding
+	mark       key.Binding
+	diff       key.Binding
+	save       key.Binding
+	saveTree   key.Binding
+	saveMode   key.Binding
 }
 
 // NewListKeyMap initializes the custom keys for the UI
@@ -67,7 +77,82 @@ func newListKeyMap() listKeyMap {
 			key.WithKeys("h", "left"),
 			key.WithHelp("esc/h/←", "back"),
 		),
+		undo: key.NewBinding(
+			key.WithKeys("u"),
+			key.WithHelp("u", "undo to revision"),
+		),
+		reveal: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "reveal value"),
+		),
+		copyValue: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "copy value"),
+		),
+		mark: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "mark diff base"),
+		),
+		diff: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "diff with marked"),
+		),
+		save: key.NewBinding(
+			key.WithKeys("s"),
+			key.WithHelp("s", "save all"),
+		),
+		saveTree: key.NewBinding(
+			key.WithKeys("S"),
+			key.WithHelp("S", "save as tree"),
+		),
+		saveMode: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "full/neat/status"),
+		),
+	}
+}
+
+// listHelpKeys returns the extra help bindings shown under the list for a state.
+func (m *model) listHelpKeys(state fsm.State) func() []key.Binding {
+	customKeys := newListKeyMap()
+	bindings := []key.Binding{customKeys.selectItem, customKeys.back}
+
+	switch state {
+	case revision:
+		bindings = append(bindings, customKeys.undo)
+	case dataKey:
+		if m.entity.Data.dataMasked {
+			bindings = append(bindings, customKeys.reveal)
+		}
+		bindings = append(bindings, customKeys.copyValue)
+	case resource:
+		bindings = append(bindings, customKeys.mark, customKeys.diff, customKeys.save, customKeys.saveTree, customKeys.saveMode)
+	case history:
+		bindings = append(bindings, customKeys.mark)
+	}
+
+	return func() []key.Binding {
+		return bindings
+	}
+}
+
+// screenKeyTaken reports whether a key is an action of the current screen
+// that the viewport must not also act on: d denies a CSR rather than
+// scrolling half a page, and f switches the spec between YAML and JSON
+// rather than paging down.
+func (m *model) screenKeyTaken(msg tea.Msg) bool {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return false
+	}
+
+	switch keyMsg.String() {
+	case "d":
+		return m.entity.GetCurrentState() == output && m.entity.Data.choice == "approval*"
+	case "f":
+		return m.entity.GetCurrentState() == spec
 	}
+	return false
 }
 
 // TODO (ozerova): decide on if this is idiomatic or not.
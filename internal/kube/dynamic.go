@@ -1,6 +1,11 @@
 package kube
 
 import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/rest"
 )
@@ -13,3 +18,12 @@ func GetDynamicClient(config *rest.Config) (*DynamicClient, error) {
 
 	return &DynamicClient{Client: dynClient}, nil
 }
+
+// GetSubresource fetches /<resource>/<name>/<subresource> for the given GVR.
+// An empty namespace addresses cluster-scoped resources.
+func (d *DynamicClient) GetSubresource(ctx context.Context, gvr schema.GroupVersionResource, namespace, name, subresource string) (*unstructured.Unstructured, error) {
+	if namespace == "" {
+		return d.Client.Resource(gvr).Get(ctx, name, metav1.GetOptions{}, subresource)
+	}
+	return d.Client.Resource(gvr).Namespace(namespace).Get(ctx, name, metav1.GetOptions{}, subresource)
+}
@@ -5,10 +5,13 @@ import (
 	"k8s.io/client-go/discovery"
 	"k8s.io/client-go/dynamic"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/kube-openapi/pkg/validation/spec"
 )
 
 // Ctx Wrapper around the entities we want to expose to a consumer
 type Ctx struct {
+	Config    *rest.Config
 	Discovery *DiscoveryClient
 	Dynamic   *DynamicClient
 	Typed     kubernetes.Interface
@@ -31,3 +34,20 @@ type DiscoveryClient struct {
 type DynamicClient struct {
 	Client dynamic.Interface
 }
+
+// SchemaDoc The component schemas of one group version's OpenAPI v3 document
+type SchemaDoc struct {
+	Components struct {
+		Schemas map[string]*spec.Schema `json:"schemas"`
+	} `json:"components"`
+
+	// The schema of each kind, indexed when the document is loaded
+	kinds map[schema.GroupVersionKind]*spec.Schema
+}
+
+// FieldDoc What `kubectl explain` shows for a single field
+type FieldDoc struct {
+	Type        string
+	Description string
+	Required    bool
+}
@@ -0,0 +1,192 @@
+package kube
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/kube-openapi/pkg/validation/spec"
+)
+
+const schemaRefPrefix = "#/components/schemas/"
+
+// cachedSchema is a schema document on disk, keyed by the URL it was served
+// from. The URL carries a hash of the document, so a changed schema (e.g. an
+// upgraded CRD) is fetched again.
+type cachedSchema struct {
+	URL    string          `json:"url"`
+	Schema json.RawMessage `json:"schema"`
+}
+
+func (d *DiscoveryClient) getSchemaCachePath(key string) string {
+	name := strings.ReplaceAll(key, "/", "_") + ".json"
+	return filepath.Join(os.Getenv("HOME"), ".kube", "traverse_openapi", name)
+}
+
+// GetSchema fetches the OpenAPI v3 document of a group version, which covers
+// CRDs as well as built-in types.
+func (d *DiscoveryClient) GetSchema(gv schema.GroupVersion) (*SchemaDoc, error) {
+	paths, err := d.Client.OpenAPIV3().Paths()
+	if err != nil {
+		return nil, err
+	}
+
+	key := "apis/" + gv.String()
+	if gv.Group == "" {
+		key = "api/" + gv.Version
+	}
+	groupVersion, ok := paths[key]
+	if !ok {
+		return nil, fmt.Errorf("no OpenAPI v3 schema published for %s", gv)
+	}
+
+	url := groupVersion.ServerRelativeURL()
+	path := d.getSchemaCachePath(key)
+
+	var raw []byte
+	var cached cachedSchema
+	if data, err := os.ReadFile(path); err == nil && json.Unmarshal(data, &cached) == nil && cached.URL == url {
+		raw = cached.Schema
+	} else {
+		raw, err = groupVersion.Schema(runtime.ContentTypeJSON)
+		if err != nil {
+			return nil, err
+		}
+		if data, err := json.Marshal(cachedSchema{URL: url, Schema: raw}); err == nil {
+			_ = os.MkdirAll(filepath.Dir(path), 0755)
+			_ = os.WriteFile(path, data, 0644)
+		}
+	}
+
+	var doc SchemaDoc
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, err
+	}
+	doc.indexKinds()
+	return &doc, nil
+}
+
+// Explain documents the field at the given path of a kind. Path elements are
+// field names, map keys or list indexes such as "[0]"; an empty path
+// documents the kind itself.
+func (doc *SchemaDoc) Explain(gvk schema.GroupVersionKind, fields []string) (FieldDoc, error) {
+	current := doc.kindSchema(gvk)
+	if current == nil {
+		return FieldDoc{}, fmt.Errorf("no schema for %s", gvk.Kind)
+	}
+
+	required := false
+	for i, name := range fields {
+		parent := doc.resolve(current)
+		if parent == nil {
+			return FieldDoc{}, fmt.Errorf("no schema for %s", strings.Join(fields[:i], "."))
+		}
+
+		if prop, ok := parent.Properties[name]; ok {
+			current = &prop
+			required = slices.Contains(parent.Required, name)
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(name, "[") && parent.Items != nil && parent.Items.Schema != nil:
+			current = parent.Items.Schema
+		case parent.AdditionalProperties != nil && parent.AdditionalProperties.Schema != nil:
+			current = parent.AdditionalProperties.Schema
+		default:
+			return FieldDoc{}, fmt.Errorf("field %q is not in the schema", name)
+		}
+		required = false
+	}
+
+	description := current.Description
+	if description == "" {
+		if resolved := doc.resolve(current); resolved != nil {
+			description = resolved.Description
+		}
+	}
+
+	typeName := doc.typeName(current)
+	if len(fields) == 0 {
+		typeName = gvk.Kind
+	}
+
+	return FieldDoc{
+		Type:        typeName,
+		Description: description,
+		Required:    required,
+	}, nil
+}
+
+// indexKinds maps each kind to its schema through the
+// x-kubernetes-group-version-kind extension, so lookups need not decode it.
+func (doc *SchemaDoc) indexKinds() {
+	doc.kinds = map[schema.GroupVersionKind]*spec.Schema{}
+	for _, s := range doc.Components.Schemas {
+		var gvks []schema.GroupVersionKind
+		if err := s.Extensions.GetObject("x-kubernetes-group-version-kind", &gvks); err != nil {
+			continue
+		}
+		for _, gvk := range gvks {
+			doc.kinds[gvk] = s
+		}
+	}
+}
+
+func (doc *SchemaDoc) kindSchema(gvk schema.GroupVersionKind) *spec.Schema {
+	return doc.kinds[gvk]
+}
+
+// resolve follows $refs, including the single-element allOf the API server
+// wraps them in to attach a description.
+func (doc *SchemaDoc) resolve(s *spec.Schema) *spec.Schema {
+	// Bounded, so a self-referencing schema cannot loop forever
+	for range 10 {
+		if s == nil {
+			return nil
+		}
+		if ref := s.Ref.String(); ref != "" {
+			s = doc.Components.Schemas[strings.TrimPrefix(ref, schemaRefPrefix)]
+			continue
+		}
+		if len(s.AllOf) == 1 {
+			s = &s.AllOf[0]
+			continue
+		}
+		return s
+	}
+	return s
+}
+
+// typeName names a schema the way kubectl explain does, e.g. "[]Container".
+func (doc *SchemaDoc) typeName(s *spec.Schema) string {
+	for s != nil && len(s.AllOf) == 1 {
+		s = &s.AllOf[0]
+	}
+	if s == nil {
+		return ""
+	}
+
+	if ref := s.Ref.String(); ref != "" {
+		name := strings.TrimPrefix(ref, schemaRefPrefix)
+		return name[strings.LastIndex(name, ".")+1:]
+	}
+	if intOrString, _ := s.Extensions.GetBool("x-kubernetes-int-or-string"); intOrString {
+		return "IntOrString"
+	}
+
+	switch {
+	case s.Type.Contains("array") && s.Items != nil && s.Items.Schema != nil:
+		return "[]" + doc.typeName(s.Items.Schema)
+	case s.Type.Contains("object") && s.AdditionalProperties != nil && s.AdditionalProperties.Schema != nil:
+		return "map[string]" + doc.typeName(s.AdditionalProperties.Schema)
+	case len(s.Type) > 0:
+		return s.Type[0]
+	}
+	return "Object"
+}
@@ -0,0 +1,80 @@
+package kube
+
+import (
+	"context"
+	"io"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// StreamOptions describes an exec or attach session against a container.
+// An empty Command attaches to the container's main process instead of
+// starting a new one.
+type StreamOptions struct {
+	Namespace string
+	Pod       string
+	Container string
+	Command   []string
+	Stdin     bool
+	TTY       bool
+}
+
+// Stream connects the given readers and writers to a container, preferring
+// WebSockets and falling back to SPDY for older API servers.
+func (c *Ctx) Stream(ctx context.Context, opts StreamOptions, stdin io.Reader, stdout, stderr io.Writer, sizes remotecommand.TerminalSizeQueue) error {
+	req := c.Typed.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(opts.Namespace).
+		Name(opts.Pod)
+
+	if len(opts.Command) > 0 {
+		req = req.SubResource("exec").VersionedParams(&corev1.PodExecOptions{
+			Container: opts.Container,
+			Command:   opts.Command,
+			Stdin:     opts.Stdin,
+			Stdout:    true,
+			Stderr:    !opts.TTY,
+			TTY:       opts.TTY,
+		}, scheme.ParameterCodec)
+	} else {
+		req = req.SubResource("attach").VersionedParams(&corev1.PodAttachOptions{
+			Container: opts.Container,
+			Stdin:     opts.Stdin,
+			Stdout:    true,
+			Stderr:    !opts.TTY,
+			TTY:       opts.TTY,
+		}, scheme.ParameterCodec)
+	}
+
+	spdyExec, err := remotecommand.NewSPDYExecutor(c.Config, "POST", req.URL())
+	if err != nil {
+		return err
+	}
+	wsExec, err := remotecommand.NewWebSocketExecutor(c.Config, "GET", req.URL().String())
+	if err != nil {
+		return err
+	}
+	exec, err := remotecommand.NewFallbackExecutor(wsExec, spdyExec, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return err
+	}
+
+	streamOpts := remotecommand.StreamOptions{
+		Stdout:            stdout,
+		Tty:               opts.TTY,
+		TerminalSizeQueue: sizes,
+	}
+	if opts.Stdin {
+		streamOpts.Stdin = stdin
+	}
+	if !opts.TTY {
+		streamOpts.Stderr = stderr
+	}
+
+	return exec.StreamWithContext(ctx, streamOpts)
+}